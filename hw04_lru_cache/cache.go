@@ -1,81 +1,200 @@
 package hw04_lru_cache //nolint:golint,stylecheck
 
-import "sync"
+import (
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
 
 type Key string
 
 type Cache interface {
 	Set(key Key, value interface{}) bool // Добавить значение в кэш по ключу
 	Get(key Key) (interface{}, bool)     // Получить значение из кэша по ключу
+	Delete(key Key) bool                 // Удалить значение из кэша по ключу
 	Clear()                              // Очистить кэш
+
+	// Получить значение из кэша, при промахе - загрузить через loader и сохранить в кэш
+	GetOrLoad(ctx context.Context, key Key, loader Loader) (interface{}, error)
+
+	Stats() Stats // Статистика работы кэша
+
+	SaveTo(w io.Writer) error   // Сохранить снимок кэша
+	LoadFrom(r io.Reader) error // Восстановить кэш из снимка
 }
 
 type lruCache struct {
+	counters // первым полем для выравнивания 64-битных счётчиков
+
 	mutex    sync.Mutex
 	capacity int               // - ёмкость (количество сохраняемых в кэше элементов)
 	queue    List              // - очередь \[последних используемых элементов\] на основе двусвязного списка
 	items    map[Key]*ListItem // - словарь, отображающий ключ (строка) на элемент очереди
 
+	ttl         time.Duration // время хранения значений, 0 - бессрочно
+	negativeTTL time.Duration // время хранения ошибок загрузки, 0 - ошибки не кэшируются
+	loads       loadGroup     // выполняющиеся загрузки
+	codec       ValueCodec    // кодирование значений при сохранении снимка
+
+	now func() time.Time // текущее время, в тестах подменяется
 }
+
 type cacheItem struct {
-	key   Key
-	value interface{}
+	key     Key
+	value   interface{}
+	err     error     // ошибка загрузки, если элемент - закэшированный промах
+	expires time.Time // момент устаревания, нулевое значение - бессрочно
 }
 
-// Добавить значение в кэш по ключу.
-func (cache *lruCache) Set(key Key, value interface{}) bool {
-	var newItem cacheItem
-	newItem.key = key
-	newItem.value = value
+// Option настраивает кэш при создании.
+type Option func(cache *lruCache)
 
-	cache.mutex.Lock()
-	defer cache.mutex.Unlock()
+// WithTTL ограничивает время хранения значений в кэше.
+func WithTTL(ttl time.Duration) Option {
+	return func(cache *lruCache) {
+		cache.ttl = ttl
+	}
+}
+
+// WithNegativeTTL включает кэширование ошибок загрузки в GetOrLoad на время ttl.
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(cache *lruCache) {
+		cache.negativeTTL = ttl
+	}
+}
+
+// момент устаревания элемента, записанного сейчас.
+func (cache *lruCache) expiresAt(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+
+	return cache.now().Add(ttl)
+}
+
+func (item cacheItem) expired(now time.Time) bool {
+	return !item.expires.IsZero() && !now.Before(item.expires)
+}
 
+// найти актуальный элемент очереди по ключу, устаревший элемент удаляется.
+// Вызывается под блокировкой.
+func (cache *lruCache) lookup(key Key) (*ListItem, bool) {
 	cachedValue, wasInCache := cache.items[key]
 
+	if !wasInCache {
+		return nil, false
+	}
+
+	if cachedValue.Value.(cacheItem).expired(cache.now()) {
+		cache.remove(cachedValue)
+		return nil, false
+	}
+
+	return cachedValue, true
+}
+
+// сохранить элемент в кэш, вернуть признак наличия значения по ключу.
+// Вызывается под блокировкой.
+func (cache *lruCache) store(newItem cacheItem) bool {
+	cachedValue, wasInCache := cache.lookup(newItem.key)
+
 	if wasInCache {
+		hadValue := cachedValue.Value.(cacheItem).err == nil
 		cache.queue.MoveToFront(cachedValue)
 		cachedValue.Value = newItem
-		return true
+		return hadValue
 	}
 
 	if cache.capacity == cache.queue.Len() {
-		delete(cache.items, cache.queue.Back().Value.(cacheItem).key)
-		cache.queue.Remove(cache.queue.Back())
+		cache.remove(cache.queue.Back())
+		atomic.AddUint64(&cache.evictions, 1)
 	}
 
-	cache.items[key] = cache.queue.PushFront(newItem)
+	cache.items[newItem.key] = cache.queue.PushFront(newItem)
+	atomic.AddInt64(&cache.size, 1)
 
 	return false
 }
 
+// удалить элемент очереди вместе с ключом. Вызывается под блокировкой.
+func (cache *lruCache) remove(cachedValue *ListItem) {
+	delete(cache.items, cachedValue.Value.(cacheItem).key)
+	cache.queue.Remove(cachedValue)
+	atomic.AddInt64(&cache.size, -1)
+}
+
+// Добавить значение в кэш по ключу.
+func (cache *lruCache) Set(key Key, value interface{}) bool {
+	var newItem cacheItem
+	newItem.key = key
+	newItem.value = value
+	newItem.expires = cache.expiresAt(cache.ttl)
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	atomic.AddUint64(&cache.sets, 1)
+
+	return cache.store(newItem)
+}
+
 // Получить значение из кэша по ключу.
 func (cache *lruCache) Get(key Key) (interface{}, bool) {
 	cache.mutex.Lock()
 	defer cache.mutex.Unlock()
 
-	cachedValue, wasInCache := cache.items[key]
+	cachedValue, wasInCache := cache.lookup(key)
 
-	if !wasInCache {
+	// закэшированная ошибка загрузки для Get - промах
+	if !wasInCache || cachedValue.Value.(cacheItem).err != nil {
+		atomic.AddUint64(&cache.misses, 1)
 		return nil, false
 	}
 
+	atomic.AddUint64(&cache.hits, 1)
 	cache.queue.MoveToFront(cachedValue)
 
 	return cachedValue.Value.(cacheItem).value, true
 }
 
+// Удалить значение из кэша по ключу, вернуть признак его наличия.
+func (cache *lruCache) Delete(key Key) bool {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cachedValue, wasInCache := cache.lookup(key)
+	if !wasInCache {
+		return false
+	}
+
+	cache.remove(cachedValue)
+
+	return cachedValue.Value.(cacheItem).err == nil
+}
+
 // Очистить кэш.
 func (cache *lruCache) Clear() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
 	cache.queue = NewList()
 	cache.items = make(map[Key]*ListItem, cache.capacity)
+	atomic.StoreInt64(&cache.size, 0)
 }
 
-func NewCache(capacity int) Cache {
+func NewCache(capacity int, options ...Option) Cache {
 	var cache lruCache
 
 	cache.capacity = capacity
+	cache.codec = GobCodec{}
+	cache.now = time.Now
 	cache.Clear()
 
+	for _, option := range options {
+		option(&cache)
+	}
+
 	return &cache
 }
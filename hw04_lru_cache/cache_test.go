@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -91,6 +92,63 @@ func TestCache(t *testing.T) {
 		require.False(t, ok)
 		require.Equal(t, nil, val)
 	})
+
+	t.Run("ttl", func(t *testing.T) {
+		ttl := time.Minute
+		c := NewCache(3, WithTTL(ttl))
+		clock := withFakeClock(c)
+
+		_ = c.Set("aaa", 100)
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 100, val)
+
+		clock.Advance(ttl - time.Nanosecond)
+
+		_, ok = c.Get("aaa")
+		require.True(t, ok)
+
+		// устаревший элемент - промах, и место в кэше он больше не занимает
+		clock.Advance(time.Nanosecond)
+
+		val, ok = c.Get("aaa")
+		require.False(t, ok)
+		require.Nil(t, val)
+		require.Equal(t, 0, c.Stats().Size)
+	})
+}
+
+// fakeClock - подменяемое время кэша, сдвигаемое тестом.
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+}
+
+// подменить время кэша c на fakeClock.
+func withFakeClock(c Cache) *fakeClock {
+	clock := &fakeClock{now: time.Now()}
+	clock.attach(c)
+
+	return clock
+}
+
+func (clock *fakeClock) attach(c Cache) {
+	c.(*lruCache).now = clock.Now
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	return clock.now
+}
+
+func (clock *fakeClock) Advance(d time.Duration) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	clock.now = clock.now.Add(d)
 }
 
 func TestCacheMultithreading(t *testing.T) {
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	cache "github.com/elak/golang_home_work/hw04_lru_cache"
+)
+
+var (
+	addr            string
+	capacity        int
+	ttl             time.Duration
+	maxValueSize    int64
+	maxSnapshotSize int64
+)
+
+const (
+	readHeaderTimeout = time.Second * 5
+	// запас на чтение снимка, самого большого тела запроса.
+	readTimeout = time.Minute
+)
+
+func init() {
+	flag.StringVar(&addr, "addr", ":8080", "address to listen on")
+	flag.IntVar(&capacity, "capacity", 1000, "maximum number of cached values")
+	flag.DurationVar(&ttl, "ttl", 0, "time to keep values, 0 - forever")
+	flag.Int64Var(&maxValueSize, "max-value-size", 1<<20, "maximum value size in bytes, 0 - unlimited")
+	flag.Int64Var(&maxSnapshotSize, "max-snapshot-size", 64<<20, "maximum size of uploaded snapshot in bytes")
+}
+
+func main() {
+	flag.Parse()
+
+	if capacity <= 0 {
+		log.Fatalf("capacity must be positive, got %d", capacity)
+	}
+
+	lru := cache.NewCache(capacity, cache.WithTTL(ttl))
+
+	// обработчик кэша - корневой, чтобы пути с ключами не очищались
+	handler := cache.Handler{
+		Cache:           lru,
+		MaxValueSize:    maxValueSize,
+		MaxSnapshotSize: maxSnapshotSize,
+		Metrics:         cache.StatsCollector{Namespace: "cachesrv", Cache: lru},
+	}
+
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: readHeaderTimeout,
+		ReadTimeout:       readTimeout,
+	}
+
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+		signal.Stop(signals)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+		defer cancel()
+
+		if err := server.Shutdown(ctx); err != nil {
+			log.Printf("failed to stop http server: %s", err)
+		}
+	}()
+
+	log.Printf("cache server is listening on %s", addr)
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("failed to start http server: %s", err)
+	}
+}
@@ -1,57 +1,59 @@
 package hw04_lru_cache //nolint:golint,stylecheck
 
 type List interface {
-	Len() int                          // длина списка
-	Front() *ListItem                  // первый элемент списка
-	Back() *ListItem                   // последний элемент списка
-	PushFront(v interface{}) *ListItem // добавить значение в начало
-	PushBack(v interface{}) *ListItem  // добавить значение в конец
-	Remove(i *ListItem)                // удалить элемент
-	MoveToFront(i *ListItem)           // переместить элемент в начало
+	Len() int                                             // длина списка
+	Front() *ListItem                                     // первый элемент списка
+	Back() *ListItem                                      // последний элемент списка
+	PushFront(v interface{}) *ListItem                    // добавить значение в начало
+	PushBack(v interface{}) *ListItem                     // добавить значение в конец
+	InsertBefore(v interface{}, mark *ListItem) *ListItem // добавить значение перед элементом
+	InsertAfter(v interface{}, mark *ListItem) *ListItem  // добавить значение после элемента
+	PushFrontList(other List)                             // добавить в начало копию значений другого списка
+	PushBackList(other List)                              // добавить в конец копию значений другого списка
+	Remove(i *ListItem)                                   // удалить элемент
+	MoveToFront(i *ListItem)                              // переместить элемент в начало
+	MoveToBack(i *ListItem)                               // переместить элемент в конец
+	MoveBefore(i, mark *ListItem)                         // переместить элемент перед другим
+	MoveAfter(i, mark *ListItem)                          // переместить элемент после другого
+	Range(f func(i *ListItem) bool)                       // обойти элементы от начала, пока f возвращает true
+	Values() []interface{}                                // значения элементов от начала к концу
 }
 
+// ListItem - элемент списка. Методы списка, получающие элемент,
+// ничего не делают, если элемент принадлежит другому списку.
 type ListItem struct {
 	Next  *ListItem
 	Prev  *ListItem
 	Value interface{}
+
+	list *list // список, в котором находится элемент
 }
 
-// Извлечь элемент из цепочки.
+// Извлечь элемент из его списка.
 func (thisItem *ListItem) Extract() {
-	if thisItem.Next != nil {
-		thisItem.Next.Prev = thisItem.Prev
-	}
-
-	if thisItem.Prev != nil {
-		thisItem.Prev.Next = thisItem.Next
+	if thisItem.list != nil {
+		thisItem.list.Remove(thisItem)
 	}
-
-	thisItem.Next = nil
-	thisItem.Prev = nil
 }
 
-// Установить следующий со взаимной привязкой.
+// Установить следующий со взаимной привязкой: newNext переносится в список элемента сразу после него.
 func (thisItem *ListItem) SetNext(newNext *ListItem) {
-	if newNext == nil {
-		thisItem.Next = newNext
+	if newNext == nil || newNext == thisItem || thisItem.list == nil {
 		return
 	}
 
-	newNext.Next = thisItem.Next
-	thisItem.Next = newNext
-	newNext.Prev = thisItem
+	newNext.Extract()
+	thisItem.list.insertAfter(newNext, thisItem)
 }
 
-// Установить предыдущий со взаимной привязкой.
+// Установить предыдущий со взаимной привязкой: newPrev переносится в список элемента сразу перед ним.
 func (thisItem *ListItem) SetPrev(newPrev *ListItem) {
-	if newPrev == nil {
-		thisItem.Prev = newPrev
+	if newPrev == nil || newPrev == thisItem || thisItem.list == nil {
 		return
 	}
 
-	newPrev.Prev = thisItem.Prev
-	thisItem.Prev = newPrev
-	newPrev.Next = thisItem
+	newPrev.Extract()
+	thisItem.list.insertBefore(newPrev, thisItem)
 }
 
 type list struct {
@@ -74,83 +76,173 @@ func (lst *list) Back() *ListItem {
 	return lst.back
 }
 
-// Добавить элемент в начало.
-func (lst *list) PushFront(v interface{}) *ListItem {
+// вставить элемент после at, при at == nil - в начало.
+func (lst *list) insertAfter(i, at *ListItem) *ListItem {
+	i.list = lst
+	i.Prev = at
+
+	// nil <- (prev) front <-> ... <-> at <-> i! <-> ... <-> back (next) -> nil
+	if at == nil {
+		i.Next = lst.front
+		lst.front = i
+	} else {
+		i.Next = at.Next
+		at.Next = i
+	}
+
+	if i.Next == nil {
+		lst.back = i
+	} else {
+		i.Next.Prev = i
+	}
+
 	lst.len++
 
-	var li ListItem
-	li.Value = v
+	return i
+}
 
-	// nil i! <- (prev) front <-> ... <-> elem <-> ... <-> back (next) -> nil
-	if lst.front != nil {
-		lst.front.SetPrev(&li)
+// вставить элемент перед at, при at == nil - в конец.
+func (lst *list) insertBefore(i, at *ListItem) *ListItem {
+	if at == nil {
+		return lst.insertAfter(i, lst.back)
 	}
 
-	lst.front = &li
+	return lst.insertAfter(i, at.Prev)
+}
 
-	if lst.back == nil {
-		lst.back = &li
+// извлечь элемент из цепочки, оставив его привязанным к списку.
+func (lst *list) unlink(i *ListItem) {
+	if i.Prev == nil {
+		lst.front = i.Next
+	} else {
+		i.Prev.Next = i.Next
 	}
 
-	return lst.Front()
+	if i.Next == nil {
+		lst.back = i.Prev
+	} else {
+		i.Next.Prev = i.Prev
+	}
+
+	i.Next = nil
+	i.Prev = nil
+
+	lst.len--
+}
+
+// Добавить элемент в начало.
+func (lst *list) PushFront(v interface{}) *ListItem {
+	return lst.insertAfter(&ListItem{Value: v}, nil)
 }
 
 // добавить значение в конец.
 func (lst *list) PushBack(v interface{}) *ListItem {
-	lst.len++
+	return lst.insertBefore(&ListItem{Value: v}, nil)
+}
 
-	var li ListItem
-	li.Value = v
+// добавить значение перед элементом, вернуть nil, если элемента нет в списке.
+func (lst *list) InsertBefore(v interface{}, mark *ListItem) *ListItem {
+	if mark == nil || mark.list != lst {
+		return nil
+	}
+
+	return lst.insertBefore(&ListItem{Value: v}, mark)
+}
 
-	// nil <- (prev) front <-> ... <-> elem <-> ... <-> back (next) -> i! nil
-	if lst.back != nil {
-		lst.back.SetNext(&li)
+// добавить значение после элемента, вернуть nil, если элемента нет в списке.
+func (lst *list) InsertAfter(v interface{}, mark *ListItem) *ListItem {
+	if mark == nil || mark.list != lst {
+		return nil
 	}
 
-	lst.back = &li
+	return lst.insertAfter(&ListItem{Value: v}, mark)
+}
 
-	if lst.front == nil {
-		lst.front = &li
+// добавить в начало копию значений другого списка, other может совпадать с самим списком.
+func (lst *list) PushFrontList(other List) {
+	for n, i := other.Len(), other.Back(); n > 0; n, i = n-1, i.Prev {
+		lst.PushFront(i.Value)
 	}
+}
 
-	return lst.Back()
+// добавить в конец копию значений другого списка, other может совпадать с самим списком.
+func (lst *list) PushBackList(other List) {
+	for n, i := other.Len(), other.Front(); n > 0; n, i = n-1, i.Next {
+		lst.PushBack(i.Value)
+	}
 }
 
 // удалить элемент.
 func (lst *list) Remove(i *ListItem) {
-	lst.len--
+	if i == nil || i.list != lst {
+		return
+	}
 
-	// nil <- (prev) front i! <-> ... <-> elem <-> ... <-> back (next) -> nil
-	if i == lst.front {
-		lst.front = i.Next
+	lst.unlink(i)
+	i.list = nil
+}
+
+// переместить элемент в начало.
+func (lst *list) MoveToFront(i *ListItem) {
+	if i == nil || i.list != lst || lst.front == i {
+		return
 	}
 
-	// nil <- (prev) front <-> ... <-> elem <-> ... <-> i! back (next) -> nil
-	if i == lst.back {
-		lst.back = i.Prev
+	lst.unlink(i)
+	lst.insertAfter(i, nil)
+}
+
+// переместить элемент в конец.
+func (lst *list) MoveToBack(i *ListItem) {
+	if i == nil || i.list != lst || lst.back == i {
+		return
 	}
 
-	i.Extract()
+	lst.unlink(i)
+	lst.insertBefore(i, nil)
 }
 
-// переместить элемент в начало.
-func (lst *list) MoveToFront(i *ListItem) {
-	if lst.front == i {
+// переместить элемент перед mark.
+func (lst *list) MoveBefore(i, mark *ListItem) {
+	if i == nil || mark == nil || i == mark || i.list != lst || mark.list != lst {
 		return
 	}
 
-	if lst.back == i {
-		lst.back = i.Prev
+	lst.unlink(i)
+	lst.insertBefore(i, mark)
+}
+
+// переместить элемент после mark.
+func (lst *list) MoveAfter(i, mark *ListItem) {
+	if i == nil || mark == nil || i == mark || i.list != lst || mark.list != lst {
+		return
+	}
+
+	lst.unlink(i)
+	lst.insertAfter(i, mark)
+}
+
+// обойти элементы от начала, пока f возвращает true.
+// Текущий элемент можно удалять из списка внутри f.
+func (lst *list) Range(f func(i *ListItem) bool) {
+	for i := lst.front; i != nil; {
+		next := i.Next
+		if !f(i) {
+			return
+		}
+		i = next
 	}
+}
 
-	i.Extract()
+// значения элементов от начала к концу.
+func (lst *list) Values() []interface{} {
+	values := make([]interface{}, 0, lst.len)
 
-	// i! <- (prev) front <-> ... <-> elem <-> ... <-> back (next) -> nil
-	if lst.front != nil {
-		lst.front.SetPrev(i)
+	for i := lst.front; i != nil; i = i.Next {
+		values = append(values, i.Value)
 	}
 
-	lst.front = i
+	return values
 }
 
 func NewList() List {
@@ -62,4 +62,144 @@ func TestList(t *testing.T) {
 
 		require.Equal(t, []int{70, 80, 60, 40, 10, 30, 50}, toSlice(l))
 	})
+
+	t.Run("insert and move", func(t *testing.T) {
+		l := NewList()
+
+		i20 := l.PushBack(20)
+		l.InsertBefore(10, i20) // [10, 20]
+		i40 := l.InsertAfter(40, i20)
+		l.InsertBefore(30, i40) // [10, 20, 30, 40]
+		require.Equal(t, []int{10, 20, 30, 40}, toSlice(l))
+		require.Equal(t, 4, l.Len())
+
+		l.MoveToBack(l.Front()) // [20, 30, 40, 10]
+		require.Equal(t, []int{20, 30, 40, 10}, toSlice(l))
+		require.Equal(t, 10, l.Back().Value)
+
+		l.MoveBefore(l.Back(), i20) // [10, 20, 30, 40]
+		require.Equal(t, []int{10, 20, 30, 40}, toSlice(l))
+
+		l.MoveAfter(i20, i40) // [10, 30, 40, 20]
+		require.Equal(t, []int{10, 30, 40, 20}, toSlice(l))
+		require.Equal(t, 20, l.Back().Value)
+
+		// перемещение относительно самого себя ничего не меняет
+		l.MoveAfter(i20, i20)
+		require.Equal(t, []int{10, 30, 40, 20}, toSlice(l))
+
+		// обратный обход согласован с прямым
+		back := make([]int, 0, l.Len())
+		for i := l.Back(); i != nil; i = i.Prev {
+			back = append(back, i.Value.(int))
+		}
+		require.Equal(t, []int{20, 40, 30, 10}, back)
+	})
+
+	t.Run("push list", func(t *testing.T) {
+		l := NewList()
+		l.PushBack(10)
+		l.PushBack(20)
+
+		other := NewList()
+		other.PushBack(30)
+		other.PushBack(40)
+
+		l.PushBackList(other) // [10, 20, 30, 40]
+		require.Equal(t, []int{10, 20, 30, 40}, toSlice(l))
+
+		l.PushFrontList(other) // [30, 40, 10, 20, 30, 40]
+		require.Equal(t, []int{30, 40, 10, 20, 30, 40}, toSlice(l))
+
+		// значения копируются, исходный список не меняется
+		require.Equal(t, []int{30, 40}, toSlice(other))
+
+		self := NewList()
+		self.PushBack(1)
+		self.PushBack(2)
+		self.PushBackList(self)
+		require.Equal(t, []int{1, 2, 1, 2}, toSlice(self))
+	})
+
+	t.Run("foreign items", func(t *testing.T) {
+		l := NewList()
+		l.PushBack(10)
+		l.PushBack(20)
+
+		other := NewList()
+		foreign := other.PushBack(30)
+
+		l.Remove(foreign)
+		l.MoveToFront(foreign)
+		l.MoveToBack(foreign)
+		l.MoveBefore(foreign, l.Front())
+		l.MoveAfter(l.Front(), foreign)
+		require.Nil(t, l.InsertBefore(40, foreign))
+		require.Nil(t, l.InsertAfter(40, foreign))
+
+		require.Equal(t, []int{10, 20}, toSlice(l))
+		require.Equal(t, 2, l.Len())
+		require.Equal(t, []int{30}, toSlice(other))
+		require.Equal(t, 1, other.Len())
+
+		// повторное удаление элемента не портит длину
+		front := l.Front()
+		l.Remove(front)
+		l.Remove(front)
+		require.Equal(t, 1, l.Len())
+		require.Equal(t, []int{20}, toSlice(l))
+	})
+
+	t.Run("item links", func(t *testing.T) {
+		l := NewList()
+		first := l.PushBack(10)
+		last := l.PushBack(20)
+
+		other := NewList()
+		moved := other.PushBack(30)
+
+		first.SetNext(moved)
+		require.Equal(t, []int{10, 30, 20}, toSlice(l))
+		require.Equal(t, 3, l.Len())
+		require.Equal(t, 0, other.Len())
+
+		last.SetPrev(first)
+		require.Equal(t, []int{30, 10, 20}, toSlice(l))
+		require.Equal(t, last, l.Back())
+
+		moved.Extract()
+		moved.Extract()
+		require.Equal(t, []int{10, 20}, toSlice(l))
+		require.Equal(t, 2, l.Len())
+
+		// элемент вне списка ничего не привязывает
+		moved.SetNext(first)
+		require.Equal(t, []int{10, 20}, toSlice(l))
+	})
+
+	t.Run("range and values", func(t *testing.T) {
+		l := NewList()
+		for _, v := range [...]int{10, 20, 30, 40} {
+			l.PushBack(v)
+		}
+
+		require.Equal(t, []interface{}{10, 20, 30, 40}, l.Values())
+		require.Equal(t, []interface{}{}, NewList().Values())
+
+		visited := make([]int, 0, l.Len())
+		l.Range(func(i *ListItem) bool {
+			visited = append(visited, i.Value.(int))
+			return i.Value.(int) < 30
+		})
+		require.Equal(t, []int{10, 20, 30}, visited)
+
+		// удаление во время обхода
+		l.Range(func(i *ListItem) bool {
+			if i.Value.(int)%20 == 0 {
+				l.Remove(i)
+			}
+			return true
+		})
+		require.Equal(t, []int{10, 30}, toSlice(l))
+	})
 }
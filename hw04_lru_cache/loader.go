@@ -0,0 +1,186 @@
+package hw04_lru_cache //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// Loader загружает значение для ключа при промахе кэша.
+type Loader func(ctx context.Context, key Key) (interface{}, error)
+
+// выполняющаяся загрузка значения, результат которой ждут все обратившиеся за ключом.
+type loadCall struct {
+	done    chan struct{} // закрывается по окончании загрузки
+	cancel  context.CancelFunc
+	waiters int // количество ожидающих результат, защищено мьютексом группы
+
+	value interface{}
+	err   error
+}
+
+// группа загрузок, объединяющая одновременные загрузки по одному ключу.
+// Загрузка выполняется со своим контекстом, который отменяется,
+// когда все ожидающие результат вызовы вышли по отмене своих контекстов.
+type loadGroup struct {
+	mutex sync.Mutex
+	calls map[Key]*loadCall // выполняющиеся загрузки
+}
+
+// выполнить load или дождаться результата уже выполняющейся загрузки по ключу.
+func (group *loadGroup) do(ctx context.Context, key Key, load func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	group.mutex.Lock()
+
+	if group.calls == nil {
+		group.calls = make(map[Key]*loadCall)
+	}
+
+	call, inFlight := group.calls[key]
+	if !inFlight {
+		call = group.start(key, load)
+	}
+	call.waiters++
+
+	group.mutex.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		group.leave(key, call)
+		return nil, ctx.Err()
+	}
+}
+
+// запустить загрузку значения. Вызывается под блокировкой.
+func (group *loadGroup) start(key Key, load func(ctx context.Context) (interface{}, error)) *loadCall {
+	loadCtx, cancel := context.WithCancel(context.Background())
+
+	call := &loadCall{
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	group.calls[key] = call
+
+	go func() {
+		defer close(call.done)
+		defer cancel()
+
+		call.value, call.err = load(loadCtx)
+
+		group.mutex.Lock()
+		defer group.mutex.Unlock()
+
+		// брошенная загрузка уже удалена из группы
+		if group.calls[key] == call {
+			delete(group.calls, key)
+		}
+	}()
+
+	return call
+}
+
+// ожидающий вышел по отмене контекста, последний ушедший отменяет загрузку.
+func (group *loadGroup) leave(key Key, call *loadCall) {
+	group.mutex.Lock()
+	defer group.mutex.Unlock()
+
+	call.waiters--
+	if call.waiters > 0 {
+		return
+	}
+
+	// следующий промах по ключу начнёт новую загрузку
+	if group.calls[key] == call {
+		delete(group.calls, key)
+	}
+	call.cancel()
+}
+
+// GetOrLoad возвращает значение из кэша, а при промахе загружает его через loader.
+// Одновременные промахи по одному ключу объединяются в одну загрузку.
+// Закэшированная ошибка загрузки, как и в Get, считается промахом.
+func (cache *lruCache) GetOrLoad(ctx context.Context, key Key, loader Loader) (interface{}, error) {
+	if value, wasInCache, err := cache.getLoaded(key); wasInCache {
+		if err == nil {
+			atomic.AddUint64(&cache.hits, 1)
+		} else {
+			atomic.AddUint64(&cache.misses, 1)
+		}
+		return value, err
+	}
+
+	atomic.AddUint64(&cache.misses, 1)
+
+	return cache.loads.do(ctx, key, func(loadCtx context.Context) (interface{}, error) {
+		// значение могла сохранить только что завершившаяся загрузка
+		if value, wasInCache, err := cache.getLoaded(key); wasInCache {
+			return value, err
+		}
+
+		value, err := loader(loadCtx, key)
+
+		cache.mutex.Lock()
+		defer cache.mutex.Unlock()
+
+		// результат брошенной загрузки никому не нужен
+		if loadCtx.Err() == nil {
+			cache.storeLoaded(key, value, err)
+		}
+
+		return value, err
+	})
+}
+
+// получить значение или закэшированную ошибку загрузки.
+func (cache *lruCache) getLoaded(key Key) (interface{}, bool, error) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cachedValue, wasInCache := cache.lookup(key)
+	if !wasInCache {
+		return nil, false, nil
+	}
+
+	item := cachedValue.Value.(cacheItem)
+	if item.err == nil {
+		cache.queue.MoveToFront(cachedValue)
+	}
+
+	return item.value, true, item.err
+}
+
+// сохранить результат загрузки. Значение, записанное через Set во время загрузки,
+// новее загруженного и не затирается. Вызывается под блокировкой.
+func (cache *lruCache) storeLoaded(key Key, value interface{}, err error) {
+	var newItem cacheItem
+	newItem.key = key
+
+	cachedValue, wasInCache := cache.lookup(key)
+	if wasInCache && cachedValue.Value.(cacheItem).err == nil {
+		return
+	}
+
+	if err == nil {
+		newItem.value = value
+		newItem.expires = cache.expiresAt(cache.ttl)
+		atomic.AddUint64(&cache.sets, 1)
+		cache.store(newItem)
+		return
+	}
+
+	if cache.negativeTTL <= 0 || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+
+	// закэшированная ошибка другой загрузки уже есть
+	if wasInCache {
+		return
+	}
+
+	newItem.err = err
+	newItem.expires = cache.expiresAt(cache.negativeTTL)
+	atomic.AddUint64(&cache.sets, 1)
+	cache.store(newItem)
+}
@@ -0,0 +1,189 @@
+package hw04_lru_cache //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheGetOrLoad(t *testing.T) {
+	errLoad := errors.New("load failed")
+
+	t.Run("load and cache", func(t *testing.T) {
+		c := NewCache(3)
+
+		var loadsCount int32
+		loader := func(ctx context.Context, key Key) (interface{}, error) {
+			atomic.AddInt32(&loadsCount, 1)
+			return string(key) + "!", nil
+		}
+
+		val, err := c.GetOrLoad(context.Background(), "aaa", loader)
+		require.NoError(t, err)
+		require.Equal(t, "aaa!", val)
+
+		val, err = c.GetOrLoad(context.Background(), "aaa", loader)
+		require.NoError(t, err)
+		require.Equal(t, "aaa!", val)
+		require.Equal(t, int32(1), atomic.LoadInt32(&loadsCount))
+
+		// загруженное значение доступно и через Get
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, "aaa!", val)
+
+		// уже лежащее в кэше значение не загружается
+		c.Set("bbb", 200)
+		val, err = c.GetOrLoad(context.Background(), "bbb", loader)
+		require.NoError(t, err)
+		require.Equal(t, 200, val)
+		require.Equal(t, int32(1), atomic.LoadInt32(&loadsCount))
+	})
+
+	t.Run("concurrent misses coalesced", func(t *testing.T) {
+		c := NewCache(3)
+
+		var loadsCount int32
+		release := make(chan struct{})
+		loader := func(ctx context.Context, key Key) (interface{}, error) {
+			atomic.AddInt32(&loadsCount, 1)
+			<-release
+			return 100, nil
+		}
+
+		callersCount := 50
+		wg := &sync.WaitGroup{}
+		wg.Add(callersCount)
+
+		for i := 0; i < callersCount; i++ {
+			go func() {
+				defer wg.Done()
+				val, err := c.GetOrLoad(context.Background(), "aaa", loader)
+				require.NoError(t, err)
+				require.Equal(t, 100, val)
+			}()
+		}
+
+		time.Sleep(time.Millisecond * 50)
+		close(release)
+		wg.Wait()
+
+		require.Equal(t, int32(1), atomic.LoadInt32(&loadsCount))
+	})
+
+	t.Run("errors not cached by default", func(t *testing.T) {
+		c := NewCache(3)
+
+		var loadsCount int32
+		loader := func(ctx context.Context, key Key) (interface{}, error) {
+			atomic.AddInt32(&loadsCount, 1)
+			return nil, errLoad
+		}
+
+		_, err := c.GetOrLoad(context.Background(), "aaa", loader)
+		require.True(t, errors.Is(err, errLoad))
+
+		_, err = c.GetOrLoad(context.Background(), "aaa", loader)
+		require.True(t, errors.Is(err, errLoad))
+		require.Equal(t, int32(2), atomic.LoadInt32(&loadsCount))
+	})
+
+	t.Run("negative ttl", func(t *testing.T) {
+		negativeTTL := time.Millisecond * 100
+		c := NewCache(3, WithNegativeTTL(negativeTTL))
+
+		var loadsCount int32
+		loader := func(ctx context.Context, key Key) (interface{}, error) {
+			atomic.AddInt32(&loadsCount, 1)
+			return nil, errLoad
+		}
+
+		_, err := c.GetOrLoad(context.Background(), "aaa", loader)
+		require.True(t, errors.Is(err, errLoad))
+
+		_, err = c.GetOrLoad(context.Background(), "aaa", loader)
+		require.True(t, errors.Is(err, errLoad))
+		require.Equal(t, int32(1), atomic.LoadInt32(&loadsCount))
+
+		// для Get закэшированная ошибка - промах
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+
+		// после устаревания ошибки загрузка повторяется
+		time.Sleep(negativeTTL)
+		_, err = c.GetOrLoad(context.Background(), "aaa", loader)
+		require.True(t, errors.Is(err, errLoad))
+		require.Equal(t, int32(2), atomic.LoadInt32(&loadsCount))
+
+		// Set заменяет закэшированную ошибку значением
+		wasInCache := c.Set("aaa", 100)
+		require.False(t, wasInCache)
+
+		val, err := c.GetOrLoad(context.Background(), "aaa", loader)
+		require.NoError(t, err)
+		require.Equal(t, 100, val)
+	})
+
+	t.Run("set during load", func(t *testing.T) {
+		c := NewCache(3)
+
+		loading := make(chan struct{})
+		setDone := make(chan struct{})
+		loader := func(ctx context.Context, key Key) (interface{}, error) {
+			close(loading)
+			<-setDone
+			return 100, nil
+		}
+
+		go func() {
+			<-loading
+			_ = c.Set("aaa", 200)
+			close(setDone)
+		}()
+
+		val, err := c.GetOrLoad(context.Background(), "aaa", loader)
+		require.NoError(t, err)
+		require.Equal(t, 100, val)
+
+		// загруженное значение старше записанного через Set и его не затирает
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 200, val)
+	})
+
+	t.Run("context cancellation", func(t *testing.T) {
+		c := NewCache(3)
+
+		loadCanceled := make(chan struct{})
+		loader := func(ctx context.Context, key Key) (interface{}, error) {
+			<-ctx.Done()
+			close(loadCanceled)
+			return nil, ctx.Err()
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer cancel()
+
+		_, err := c.GetOrLoad(ctx, "aaa", loader)
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+
+		// единственный ожидающий ушёл - загрузка отменена
+		select {
+		case <-loadCanceled:
+		case <-time.After(time.Second):
+			require.Fail(t, "load was not canceled")
+		}
+
+		// следующий промах начинает новую загрузку
+		val, err := c.GetOrLoad(context.Background(), "aaa", func(ctx context.Context, key Key) (interface{}, error) {
+			return 100, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 100, val)
+	})
+}
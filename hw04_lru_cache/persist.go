@@ -0,0 +1,173 @@
+package hw04_lru_cache //nolint:golint,stylecheck
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const snapshotVersion = 1
+
+var (
+	ErrSnapshotVersion = errors.New("unsupported snapshot version")
+	ErrSkippedValues   = errors.New("values skipped in snapshot")
+)
+
+// ValueCodec кодирует значения кэша для снимка.
+type ValueCodec interface {
+	Encode(value interface{}) ([]byte, error)
+	Decode(data []byte) (interface{}, error)
+}
+
+// GobCodec кодирует значения через encoding/gob.
+// Типы значений, кроме встроенных, должны быть зарегистрированы через gob.Register.
+type GobCodec struct{}
+
+func (GobCodec) Encode(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(&value)
+
+	return buf.Bytes(), err
+}
+
+func (GobCodec) Decode(data []byte) (interface{}, error) {
+	var value interface{}
+	err := gob.NewDecoder(bytes.NewReader(data)).Decode(&value)
+
+	return value, err
+}
+
+// WithCodec задаёт кодирование значений в снимках кэша, по умолчанию - GobCodec.
+func WithCodec(codec ValueCodec) Option {
+	return func(cache *lruCache) {
+		cache.codec = codec
+	}
+}
+
+type snapshotHeader struct {
+	Version int
+}
+
+type snapshotEntry struct {
+	Key     Key
+	Value   []byte
+	Expires int64 // unix-время устаревания в наносекундах, 0 - бессрочно
+}
+
+// SaveTo записывает элементы кэша от давно использованных к недавним.
+// Закэшированные ошибки загрузки не сохраняются.
+// Значения, которые не удалось закодировать, пропускаются: снимок остаётся целым,
+// а SaveTo возвращает ErrSkippedValues с ключами пропущенных значений.
+func (cache *lruCache) SaveTo(w io.Writer) error {
+	// копируем элементы под блокировкой, кодируем и пишем - без неё
+	cache.mutex.Lock()
+	items := make([]cacheItem, 0, cache.queue.Len())
+	for i := cache.queue.Back(); i != nil; i = i.Prev {
+		item := i.Value.(cacheItem)
+		if item.err == nil {
+			items = append(items, item)
+		}
+	}
+	cache.mutex.Unlock()
+
+	now := cache.now()
+
+	// значения кодируются до записи, чтобы ошибка кодирования не оборвала снимок
+	entries := make([]snapshotEntry, 0, len(items))
+	var skipped []string
+
+	for _, item := range items {
+		if item.expired(now) {
+			continue
+		}
+
+		data, err := cache.codec.Encode(item.value)
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%q (%s)", item.key, err))
+			continue
+		}
+
+		entry := snapshotEntry{Key: item.key, Value: data}
+		if !item.expires.IsZero() {
+			entry.Expires = item.expires.UnixNano()
+		}
+		entries = append(entries, entry)
+	}
+
+	encoder := gob.NewEncoder(w)
+
+	if err := encoder.Encode(snapshotHeader{Version: snapshotVersion}); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	if len(skipped) > 0 {
+		return fmt.Errorf("%w: %s", ErrSkippedValues, strings.Join(skipped, ", "))
+	}
+
+	return nil
+}
+
+// LoadFrom добавляет в кэш элементы снимка, сохраняя их порядок использования и время устаревания.
+// При ошибке чтения кэш не изменяется.
+func (cache *lruCache) LoadFrom(r io.Reader) error {
+	decoder := gob.NewDecoder(r)
+
+	var header snapshotHeader
+	if err := decoder.Decode(&header); err != nil {
+		return err
+	}
+
+	if header.Version != snapshotVersion {
+		return fmt.Errorf("%w: %d", ErrSnapshotVersion, header.Version)
+	}
+
+	var items []cacheItem
+
+	for {
+		var entry snapshotEntry
+
+		err := decoder.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		value, err := cache.codec.Decode(entry.Value)
+		if err != nil {
+			return fmt.Errorf("decoding value of %q: %w", entry.Key, err)
+		}
+
+		item := cacheItem{key: entry.Key, value: value}
+		if entry.Expires != 0 {
+			item.expires = time.Unix(0, entry.Expires)
+		}
+
+		items = append(items, item)
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	now := cache.now()
+
+	// снимок идёт от давних к недавним, последний записанный окажется в начале очереди
+	for _, item := range items {
+		if !item.expired(now) {
+			cache.store(item)
+		}
+	}
+
+	return nil
+}
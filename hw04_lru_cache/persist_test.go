@@ -0,0 +1,156 @@
+package hw04_lru_cache //nolint:golint,stylecheck
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// кодирует целые числа строками.
+type intCodec struct{}
+
+func (intCodec) Encode(value interface{}) ([]byte, error) {
+	return []byte(strconv.Itoa(value.(int))), nil
+}
+
+func (intCodec) Decode(data []byte) (interface{}, error) {
+	return strconv.Atoi(string(data))
+}
+
+func TestCachePersistence(t *testing.T) {
+	t.Run("save and load", func(t *testing.T) {
+		c := NewCache(3)
+		_ = c.Set("aaa", 100)
+		_ = c.Set("bbb", "200")
+		_ = c.Set("ccc", 300)
+		_, _ = c.Get("aaa") // порядок использования: aaa, ccc, bbb
+
+		var buf bytes.Buffer
+		require.NoError(t, c.SaveTo(&buf))
+
+		restored := NewCache(3)
+		require.NoError(t, restored.LoadFrom(&buf))
+		require.Equal(t, 3, restored.Stats().Size)
+
+		// наименее используемый bbb вытесняется первым
+		_ = restored.Set("ddd", 400)
+		_, ok := restored.Get("bbb")
+		require.False(t, ok)
+
+		val, ok := restored.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 100, val)
+
+		val, ok = restored.Get("ccc")
+		require.True(t, ok)
+		require.Equal(t, 300, val)
+	})
+
+	t.Run("smaller capacity keeps recent", func(t *testing.T) {
+		c := NewCache(5)
+		for i := 0; i < 5; i++ {
+			_ = c.Set(Key(strconv.Itoa(i)), i)
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, c.SaveTo(&buf))
+
+		restored := NewCache(2)
+		require.NoError(t, restored.LoadFrom(&buf))
+
+		_, ok := restored.Get("2")
+		require.False(t, ok)
+
+		_, ok = restored.Get("3")
+		require.True(t, ok)
+
+		_, ok = restored.Get("4")
+		require.True(t, ok)
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		ttl := time.Minute
+		c := NewCache(3, WithTTL(ttl))
+		clock := withFakeClock(c)
+		_ = c.Set("aaa", 100)
+
+		clock.Advance(ttl / 2)
+		_ = c.Set("bbb", 200)
+
+		var buf bytes.Buffer
+		require.NoError(t, c.SaveTo(&buf))
+
+		// восстановленные элементы устаревают в те же моменты
+		restored := NewCache(3)
+		clock.attach(restored)
+		require.NoError(t, restored.LoadFrom(&buf))
+
+		clock.Advance(ttl / 2)
+
+		_, ok := restored.Get("aaa")
+		require.False(t, ok)
+
+		_, ok = restored.Get("bbb")
+		require.True(t, ok)
+	})
+
+	t.Run("custom codec", func(t *testing.T) {
+		c := NewCache(3, WithCodec(intCodec{}))
+		_ = c.Set("aaa", 100)
+
+		var buf bytes.Buffer
+		require.NoError(t, c.SaveTo(&buf))
+
+		restored := NewCache(3, WithCodec(intCodec{}))
+		require.NoError(t, restored.LoadFrom(&buf))
+
+		val, ok := restored.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, 100, val)
+	})
+
+	t.Run("value without codec", func(t *testing.T) {
+		type unregistered struct{ Value int }
+
+		c := NewCache(3)
+		_ = c.Set("aaa", 100)
+		_ = c.Set("bbb", unregistered{200})
+		_ = c.Set("ccc", 300)
+
+		// незакодированное значение пропускается, остальной снимок цел
+		var buf bytes.Buffer
+		err := c.SaveTo(&buf)
+		require.True(t, errors.Is(err, ErrSkippedValues))
+		require.Contains(t, err.Error(), `"bbb"`)
+
+		restored := NewCache(3)
+		require.NoError(t, restored.LoadFrom(&buf))
+		require.Equal(t, 2, restored.Stats().Size)
+
+		_, ok := restored.Get("ccc")
+		require.True(t, ok)
+	})
+
+	t.Run("broken snapshot", func(t *testing.T) {
+		c := NewCache(3)
+		_ = c.Set("aaa", 100)
+
+		var buf bytes.Buffer
+		require.NoError(t, c.SaveTo(&buf))
+
+		restored := NewCache(3)
+		err := restored.LoadFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-1]))
+		require.Error(t, err)
+		require.Equal(t, 0, restored.Stats().Size)
+
+		buf.Reset()
+		require.NoError(t, gob.NewEncoder(&buf).Encode(snapshotHeader{Version: snapshotVersion + 1}))
+		err = restored.LoadFrom(&buf)
+		require.True(t, errors.Is(err, ErrSnapshotVersion))
+	})
+}
@@ -0,0 +1,278 @@
+package hw04_lru_cache //nolint:golint,stylecheck
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// таймаут запроса клиента по умолчанию.
+const defaultRemoteTimeout = time.Second * 10
+
+var (
+	ErrNotBytes         = errors.New("remote cache value must be a byte slice or a string")
+	ErrUnexpectedStatus = errors.New("unexpected response status")
+)
+
+// RemoteCache - клиент кэша, предоставленного через Handler.
+// Значения возвращаются как []byte, записывать можно []byte и string.
+// Методы без возврата ошибки при сбоях обращения к серверу
+// ведут себя как при промахе и сообщают ошибку в OnError,
+// варианты с контекстом возвращают ошибку.
+type RemoteCache struct {
+	OnError func(err error) // обработчик ошибок обращения к серверу, может быть nil
+
+	baseURL string
+	client  *http.Client
+	loads   loadGroup
+}
+
+// NewRemoteCache создаёт клиент кэша по адресу сервера, при client == nil используется
+// клиент с таймаутом запроса 10 секунд, не следующий перенаправлениям.
+// Перенаправления сервер кэша не отправляет, поэтому ответ 3xx - ErrUnexpectedStatus.
+func NewRemoteCache(baseURL string, client *http.Client) *RemoteCache {
+	if client == nil {
+		client = &http.Client{
+			Timeout: defaultRemoteTimeout,
+			// при перенаправлении PUT превратился бы в GET другого ключа
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+
+	return &RemoteCache{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+	}
+}
+
+func (cache *RemoteCache) reportError(err error) {
+	if cache.OnError != nil {
+		cache.OnError(err)
+	}
+}
+
+// выполнить запрос, вернуть ответ с одним из ожидаемых статусов.
+func (cache *RemoteCache) do(ctx context.Context, method, path string, body io.Reader, expected ...int) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, cache.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := cache.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+
+	resp.Body.Close()
+
+	return nil, fmt.Errorf("%w: %s %s: %s", ErrUnexpectedStatus, method, path, resp.Status)
+}
+
+func keyPath(key Key) string {
+	return keysPath + url.PathEscape(string(key))
+}
+
+// Добавить значение в кэш по ключу.
+func (cache *RemoteCache) Set(key Key, value interface{}) bool {
+	wasInCache, err := cache.SetContext(context.Background(), key, value)
+	if err != nil {
+		cache.reportError(err)
+	}
+
+	return wasInCache
+}
+
+// SetContext добавляет значение в кэш по ключу, возвращает признак наличия значения по ключу.
+func (cache *RemoteCache) SetContext(ctx context.Context, key Key, value interface{}) (bool, error) {
+	var data []byte
+
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return false, fmt.Errorf("%w: %T", ErrNotBytes, value)
+	}
+
+	resp, err := cache.do(ctx, http.MethodPut, keyPath(key), bytes.NewReader(data), http.StatusOK, http.StatusCreated)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// Получить значение из кэша по ключу.
+func (cache *RemoteCache) Get(key Key) (interface{}, bool) {
+	value, wasInCache, err := cache.GetContext(context.Background(), key)
+	if err != nil {
+		cache.reportError(err)
+		return nil, false
+	}
+
+	if !wasInCache {
+		return nil, false
+	}
+
+	return value, true
+}
+
+// GetContext получает значение из кэша по ключу.
+func (cache *RemoteCache) GetContext(ctx context.Context, key Key) ([]byte, bool, error) {
+	resp, err := cache.do(ctx, http.MethodGet, keyPath(key), nil, http.StatusOK, http.StatusNotFound)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	value, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+// Удалить значение из кэша по ключу.
+func (cache *RemoteCache) Delete(key Key) bool {
+	wasInCache, err := cache.DeleteContext(context.Background(), key)
+	if err != nil {
+		cache.reportError(err)
+	}
+
+	return wasInCache
+}
+
+// DeleteContext удаляет значение из кэша по ключу, возвращает признак его наличия.
+func (cache *RemoteCache) DeleteContext(ctx context.Context, key Key) (bool, error) {
+	resp, err := cache.do(ctx, http.MethodDelete, keyPath(key), nil, http.StatusNoContent, http.StatusNotFound)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusNoContent, nil
+}
+
+// Очистить кэш.
+func (cache *RemoteCache) Clear() {
+	if err := cache.ClearContext(context.Background()); err != nil {
+		cache.reportError(err)
+	}
+}
+
+// ClearContext очищает кэш.
+func (cache *RemoteCache) ClearContext(ctx context.Context) error {
+	resp, err := cache.do(ctx, http.MethodDelete, keysPath, nil, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
+
+// GetOrLoad возвращает значение с сервера, а при промахе загружает его через loader и записывает на сервер.
+// Одновременные промахи по одному ключу в пределах клиента объединяются в одну загрузку.
+func (cache *RemoteCache) GetOrLoad(ctx context.Context, key Key, loader Loader) (interface{}, error) {
+	value, wasInCache, err := cache.GetContext(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if wasInCache {
+		return value, nil
+	}
+
+	return cache.loads.do(ctx, key, func(loadCtx context.Context) (interface{}, error) {
+		value, err := loader(loadCtx, key)
+		if err == nil {
+			// значение возвращается, даже если записать его на сервер не удалось
+			if _, setErr := cache.SetContext(loadCtx, key, value); setErr != nil {
+				cache.reportError(setErr)
+			}
+		}
+
+		return value, err
+	})
+}
+
+// Статистика работы кэша, при ошибке - нулевая.
+func (cache *RemoteCache) Stats() Stats {
+	stats, err := cache.StatsContext(context.Background())
+	if err != nil {
+		cache.reportError(err)
+	}
+
+	return stats
+}
+
+// StatsContext получает статистику работы кэша.
+func (cache *RemoteCache) StatsContext(ctx context.Context) (Stats, error) {
+	resp, err := cache.do(ctx, http.MethodGet, "/stats", nil, http.StatusOK)
+	if err != nil {
+		return Stats{}, err
+	}
+	defer resp.Body.Close()
+
+	var stats Stats
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return Stats{}, err
+	}
+
+	return stats, nil
+}
+
+// Сохранить снимок кэша сервера.
+func (cache *RemoteCache) SaveTo(w io.Writer) error {
+	return cache.SaveToContext(context.Background(), w)
+}
+
+// SaveToContext сохраняет снимок кэша сервера.
+func (cache *RemoteCache) SaveToContext(ctx context.Context, w io.Writer) error {
+	resp, err := cache.do(ctx, http.MethodGet, "/snapshot", nil, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, err = io.Copy(w, resp.Body)
+
+	return err
+}
+
+// Восстановить кэш сервера из снимка.
+func (cache *RemoteCache) LoadFrom(r io.Reader) error {
+	return cache.LoadFromContext(context.Background(), r)
+}
+
+// LoadFromContext восстанавливает кэш сервера из снимка.
+func (cache *RemoteCache) LoadFromContext(ctx context.Context, r io.Reader) error {
+	resp, err := cache.do(ctx, http.MethodPut, "/snapshot", r, http.StatusNoContent)
+	if err != nil {
+		return err
+	}
+
+	return resp.Body.Close()
+}
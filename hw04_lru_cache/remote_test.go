@@ -0,0 +1,229 @@
+package hw04_lru_cache //nolint:golint,stylecheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteCache(t *testing.T) {
+	newRemote := func(t *testing.T, capacity int) (Cache, Cache) {
+		local := NewCache(capacity)
+		server := httptest.NewServer(Handler{Cache: local})
+		t.Cleanup(server.Close)
+
+		remote := NewRemoteCache(server.URL, server.Client())
+		remote.OnError = func(err error) {
+			require.NoError(t, err)
+		}
+
+		return remote, local
+	}
+
+	t.Run("simple", func(t *testing.T) {
+		c, _ := newRemote(t, 5)
+
+		wasInCache := c.Set("aaa", []byte("100"))
+		require.False(t, wasInCache)
+
+		wasInCache = c.Set("aaa", "300")
+		require.True(t, wasInCache)
+
+		val, ok := c.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, []byte("300"), val)
+
+		val, ok = c.Get("ccc")
+		require.False(t, ok)
+		require.Nil(t, val)
+
+		// ключи со спецсимволами
+		_ = c.Set("a/b c?d", "x")
+		val, ok = c.Get("a/b c?d")
+		require.True(t, ok)
+		require.Equal(t, []byte("x"), val)
+
+		require.True(t, c.Delete("aaa"))
+		require.False(t, c.Delete("aaa"))
+
+		c.Clear()
+		_, ok = c.Get("a/b c?d")
+		require.False(t, ok)
+	})
+
+	t.Run("purge logic", func(t *testing.T) {
+		c, _ := newRemote(t, 3)
+
+		_ = c.Set("aaa", "100")
+		_ = c.Set("bbb", "200")
+		_ = c.Set("ccc", "300")
+		_, _ = c.Get("aaa")
+		_ = c.Set("ddd", "400")
+
+		_, ok := c.Get("bbb")
+		require.False(t, ok)
+
+		require.Equal(t, Stats{Hits: 1, Misses: 1, Sets: 4, Evictions: 1, Size: 3, Capacity: 3}, c.Stats())
+	})
+
+	t.Run("not bytes", func(t *testing.T) {
+		c := NewRemoteCache("http://127.0.0.1:0", nil)
+
+		var reported error
+		c.OnError = func(err error) {
+			reported = err
+		}
+
+		require.False(t, c.Set("aaa", 100))
+		require.True(t, errors.Is(reported, ErrNotBytes))
+	})
+
+	t.Run("server unavailable", func(t *testing.T) {
+		server := httptest.NewServer(Handler{Cache: NewCache(3)})
+		server.Close()
+
+		c := NewRemoteCache(server.URL, nil)
+
+		var errorsCount int
+		c.OnError = func(err error) {
+			errorsCount++
+		}
+
+		_, ok := c.Get("aaa")
+		require.False(t, ok)
+		require.Equal(t, Stats{}, c.Stats())
+		require.Equal(t, 2, errorsCount)
+
+		_, err := c.GetOrLoad(context.Background(), "aaa", func(ctx context.Context, key Key) (interface{}, error) {
+			return []byte("100"), nil
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("keys with path segments", func(t *testing.T) {
+		local := NewCache(5)
+		// обработчик, как в cachesrv
+		server := httptest.NewServer(Handler{Cache: local, Metrics: StatsCollector{Namespace: "cachesrv", Cache: local}})
+		t.Cleanup(server.Close)
+
+		c := NewRemoteCache(server.URL, nil)
+		c.OnError = func(err error) {
+			require.NoError(t, err)
+		}
+
+		keys := []Key{"a/../b", "./c", "d//e", "f/.", "g%2Fh"}
+		for _, key := range keys {
+			require.False(t, c.Set(key, string(key)))
+		}
+
+		for _, key := range keys {
+			val, ok := local.Get(key)
+			require.True(t, ok, key)
+			require.Equal(t, []byte(key), val)
+
+			val, ok = c.Get(key)
+			require.True(t, ok, key)
+			require.Equal(t, []byte(key), val)
+		}
+
+		_, ok := local.Get("b")
+		require.False(t, ok)
+
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL+"/metrics", nil)
+		require.NoError(t, err)
+		resp, err := server.Client().Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		server := httptest.NewServer(http.RedirectHandler("/keys/other", http.StatusMovedPermanently))
+		t.Cleanup(server.Close)
+
+		c := NewRemoteCache(server.URL, nil)
+
+		var reported error
+		c.OnError = func(err error) {
+			reported = err
+		}
+
+		require.False(t, c.Set("aaa", "100"))
+		require.True(t, errors.Is(reported, ErrUnexpectedStatus))
+	})
+
+	t.Run("context", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		t.Cleanup(server.Close)
+		defer close(release)
+
+		c := NewRemoteCache(server.URL, nil)
+		require.Equal(t, defaultRemoteTimeout, c.client.Timeout)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer cancel()
+
+		_, _, err := c.GetContext(ctx, "aaa")
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("get or load", func(t *testing.T) {
+		c, local := newRemote(t, 3)
+
+		var loadsCount int32
+		loader := func(ctx context.Context, key Key) (interface{}, error) {
+			atomic.AddInt32(&loadsCount, 1)
+			time.Sleep(time.Millisecond * 50)
+			return []byte("100"), nil
+		}
+
+		callersCount := 10
+		wg := &sync.WaitGroup{}
+		wg.Add(callersCount)
+
+		for i := 0; i < callersCount; i++ {
+			go func() {
+				defer wg.Done()
+				val, err := c.GetOrLoad(context.Background(), "aaa", loader)
+				require.NoError(t, err)
+				require.Equal(t, []byte("100"), val)
+			}()
+		}
+		wg.Wait()
+
+		require.Equal(t, int32(1), atomic.LoadInt32(&loadsCount))
+
+		// загруженное значение записано на сервер
+		val, ok := local.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, []byte("100"), val)
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		c, _ := newRemote(t, 3)
+		_ = c.Set("aaa", "100")
+
+		var buf bytes.Buffer
+		require.NoError(t, c.SaveTo(&buf))
+
+		restored, _ := newRemote(t, 3)
+		require.NoError(t, restored.LoadFrom(&buf))
+
+		val, ok := restored.Get("aaa")
+		require.True(t, ok)
+		require.Equal(t, []byte("100"), val)
+
+		require.Error(t, restored.LoadFrom(bytes.NewReader([]byte("garbage"))))
+	})
+}
@@ -0,0 +1,156 @@
+package hw04_lru_cache //nolint:golint,stylecheck
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	keysPath = "/keys/"
+	// ограничение размера снимка по умолчанию.
+	defaultMaxSnapshotSize = 64 << 20
+)
+
+// Handler предоставляет доступ к кэшу по HTTP. Значения кэша - []byte.
+//
+//	GET    /keys/{key} - значение, 404 при промахе
+//	PUT    /keys/{key} - записать тело запроса, 200 - значение заменено, 201 - добавлено
+//	DELETE /keys/{key} - удалить значение, 404 при отсутствии
+//	DELETE /keys/      - очистить кэш
+//	GET    /stats      - статистика кэша в JSON
+//	GET    /snapshot   - снимок кэша
+//	PUT    /snapshot   - восстановить кэш из снимка
+//	GET    /metrics    - метрики, если задан Metrics
+//
+// Handler должен быть корневым обработчиком сервера: http.ServeMux очищает пути,
+// и ключи с сегментами "..", "." или "//" перенаправлялись бы на другие ключи.
+type Handler struct {
+	Cache           Cache
+	MaxValueSize    int64        // ограничение размера значения, 0 - без ограничения
+	MaxSnapshotSize int64        // ограничение размера восстанавливаемого снимка, 0 - 64 МиБ
+	Metrics         http.Handler // обработчик /metrics, может быть nil
+}
+
+func (handler Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// ключ берётся из экранированного пути: в нём может быть "/"
+	escapedPath := r.URL.EscapedPath()
+
+	switch {
+	case escapedPath == keysPath:
+		handler.serveClear(w, r)
+	case strings.HasPrefix(escapedPath, keysPath):
+		key, err := url.PathUnescape(strings.TrimPrefix(escapedPath, keysPath))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		handler.serveKey(w, r, Key(key))
+	case r.URL.Path == "/metrics" && handler.Metrics != nil:
+		handler.Metrics.ServeHTTP(w, r)
+	case r.URL.Path == "/stats":
+		handler.serveStats(w, r)
+	case r.URL.Path == "/snapshot":
+		handler.serveSnapshot(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (handler Handler) serveKey(w http.ResponseWriter, r *http.Request, key Key) {
+	switch r.Method {
+	case http.MethodGet:
+		value, wasInCache := handler.Cache.Get(key)
+		if !wasInCache {
+			http.NotFound(w, r)
+			return
+		}
+
+		data, isBytes := value.([]byte)
+		if !isBytes {
+			http.Error(w, "value is not a byte slice", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(data)
+
+	case http.MethodPut:
+		body := r.Body
+		if handler.MaxValueSize > 0 {
+			body = http.MaxBytesReader(w, body, handler.MaxValueSize)
+		}
+
+		data, err := ioutil.ReadAll(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if handler.Cache.Set(key, data) {
+			w.WriteHeader(http.StatusOK)
+		} else {
+			w.WriteHeader(http.StatusCreated)
+		}
+
+	case http.MethodDelete:
+		if !handler.Cache.Delete(key) {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+func (handler Handler) serveClear(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete {
+		methodNotAllowed(w, http.MethodDelete)
+		return
+	}
+
+	handler.Cache.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (handler Handler) serveStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(handler.Cache.Stats())
+}
+
+func (handler Handler) serveSnapshot(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_ = handler.Cache.SaveTo(w)
+
+	case http.MethodPut:
+		limit := handler.MaxSnapshotSize
+		if limit <= 0 {
+			limit = defaultMaxSnapshotSize
+		}
+
+		if err := handler.Cache.LoadFrom(http.MaxBytesReader(w, r.Body, limit)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPut)
+	}
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+}
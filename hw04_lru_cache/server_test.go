@@ -0,0 +1,80 @@
+package hw04_lru_cache //nolint:golint,stylecheck
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	serve := func(handler Handler, method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+
+	t.Run("keys", func(t *testing.T) {
+		handler := Handler{Cache: NewCache(3)}
+
+		require.Equal(t, http.StatusNotFound, serve(handler, http.MethodGet, "/keys/aaa", "").Code)
+		require.Equal(t, http.StatusCreated, serve(handler, http.MethodPut, "/keys/aaa", "100").Code)
+		require.Equal(t, http.StatusOK, serve(handler, http.MethodPut, "/keys/aaa", "200").Code)
+
+		rec := serve(handler, http.MethodGet, "/keys/aaa", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "200", rec.Body.String())
+
+		require.Equal(t, http.StatusNoContent, serve(handler, http.MethodDelete, "/keys/aaa", "").Code)
+		require.Equal(t, http.StatusNotFound, serve(handler, http.MethodDelete, "/keys/aaa", "").Code)
+
+		rec = serve(handler, http.MethodPost, "/keys/aaa", "")
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+		require.Equal(t, "GET, PUT, DELETE", rec.Header().Get("Allow"))
+
+		require.Equal(t, http.StatusNotFound, serve(handler, http.MethodGet, "/unknown", "").Code)
+	})
+
+	t.Run("max value size", func(t *testing.T) {
+		handler := Handler{Cache: NewCache(3), MaxValueSize: 3}
+
+		require.Equal(t, http.StatusCreated, serve(handler, http.MethodPut, "/keys/aaa", "100").Code)
+		require.Equal(t, http.StatusRequestEntityTooLarge, serve(handler, http.MethodPut, "/keys/bbb", "1000").Code)
+		require.Equal(t, http.StatusNotFound, serve(handler, http.MethodGet, "/keys/bbb", "").Code)
+	})
+
+	t.Run("max snapshot size", func(t *testing.T) {
+		c := NewCache(3)
+		_ = c.Set("aaa", []byte("100"))
+
+		var snapshot strings.Builder
+		require.NoError(t, c.SaveTo(&snapshot))
+
+		handler := Handler{Cache: NewCache(3), MaxSnapshotSize: int64(snapshot.Len() - 1)}
+		require.Equal(t, http.StatusBadRequest, serve(handler, http.MethodPut, "/snapshot", snapshot.String()).Code)
+		require.Equal(t, http.StatusNotFound, serve(handler, http.MethodGet, "/keys/aaa", "").Code)
+
+		handler.MaxSnapshotSize = int64(snapshot.Len())
+		require.Equal(t, http.StatusNoContent, serve(handler, http.MethodPut, "/snapshot", snapshot.String()).Code)
+		require.Equal(t, http.StatusOK, serve(handler, http.MethodGet, "/keys/aaa", "").Code)
+	})
+
+	t.Run("not bytes", func(t *testing.T) {
+		c := NewCache(3)
+		_ = c.Set("aaa", 100)
+
+		handler := Handler{Cache: c}
+		require.Equal(t, http.StatusInternalServerError, serve(handler, http.MethodGet, "/keys/aaa", "").Code)
+	})
+
+	t.Run("stats", func(t *testing.T) {
+		handler := Handler{Cache: NewCache(3)}
+		serve(handler, http.MethodPut, "/keys/aaa", "100")
+
+		rec := serve(handler, http.MethodGet, "/stats", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.JSONEq(t, `{"Hits":0,"Misses":0,"Sets":1,"Evictions":0,"Size":1,"Capacity":3}`, rec.Body.String())
+	})
+}
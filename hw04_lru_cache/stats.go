@@ -0,0 +1,75 @@
+package hw04_lru_cache //nolint:golint,stylecheck
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// Stats - снимок статистики работы кэша.
+type Stats struct {
+	Hits      uint64 // попадания
+	Misses    uint64 // промахи
+	Sets      uint64 // записи значений, в т.ч. загруженных через GetOrLoad
+	Evictions uint64 // вытеснения из-за превышения ёмкости
+	Size      int    // текущее количество элементов
+	Capacity  int    // ёмкость
+}
+
+// счётчики статистики, изменяются атомарно.
+type counters struct {
+	hits      uint64
+	misses    uint64
+	sets      uint64
+	evictions uint64
+	size      int64
+}
+
+// Статистика работы кэша.
+func (cache *lruCache) Stats() Stats {
+	return Stats{
+		Hits:      atomic.LoadUint64(&cache.hits),
+		Misses:    atomic.LoadUint64(&cache.misses),
+		Sets:      atomic.LoadUint64(&cache.sets),
+		Evictions: atomic.LoadUint64(&cache.evictions),
+		Size:      int(atomic.LoadInt64(&cache.size)),
+		Capacity:  cache.capacity,
+	}
+}
+
+// StatsCollector публикует статистику кэша в текстовом формате Prometheus.
+type StatsCollector struct {
+	Namespace string // префикс имён метрик
+	Cache     Cache
+}
+
+// WriteTo выводит метрики кэша в w.
+func (collector StatsCollector) WriteTo(w io.Writer) (int64, error) {
+	stats := collector.Cache.Stats()
+
+	var buf bytes.Buffer
+
+	writeMetric := func(name, kind, help string, value interface{}) {
+		name = collector.Namespace + "_" + name
+		fmt.Fprintf(&buf, "# HELP %s %s\n", name, help)
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, kind)
+		fmt.Fprintf(&buf, "%s %v\n", name, value)
+	}
+
+	writeMetric("hits_total", "counter", "Number of cache hits.", stats.Hits)
+	writeMetric("misses_total", "counter", "Number of cache misses.", stats.Misses)
+	writeMetric("sets_total", "counter", "Number of values stored in the cache.", stats.Sets)
+	writeMetric("evictions_total", "counter", "Number of items evicted due to capacity.", stats.Evictions)
+	writeMetric("size", "gauge", "Current number of items in the cache.", stats.Size)
+	writeMetric("capacity", "gauge", "Maximum number of items in the cache.", stats.Capacity)
+
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP отдаёт метрики кэша, чтобы collector можно было зарегистрировать как /metrics.
+func (collector StatsCollector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = collector.WriteTo(w)
+}
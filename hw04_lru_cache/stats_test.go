@@ -0,0 +1,93 @@
+package hw04_lru_cache //nolint:golint,stylecheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheStats(t *testing.T) {
+	t.Run("counters", func(t *testing.T) {
+		c := NewCache(2)
+
+		require.Equal(t, Stats{Capacity: 2}, c.Stats())
+
+		_ = c.Set("aaa", 100)
+		_ = c.Set("bbb", 200)
+		_ = c.Set("aaa", 300)
+		_, _ = c.Get("aaa")
+		_, _ = c.Get("ccc")
+		_ = c.Set("ccc", 400) // вытесняет bbb
+
+		require.Equal(t, Stats{
+			Hits:      1,
+			Misses:    1,
+			Sets:      4,
+			Evictions: 1,
+			Size:      2,
+			Capacity:  2,
+		}, c.Stats())
+
+		// очистка сбрасывает только размер
+		c.Clear()
+		stats := c.Stats()
+		require.Equal(t, 0, stats.Size)
+		require.Equal(t, uint64(4), stats.Sets)
+	})
+
+	t.Run("get or load", func(t *testing.T) {
+		c := NewCache(2)
+		loader := func(ctx context.Context, key Key) (interface{}, error) {
+			return 100, nil
+		}
+
+		_, _ = c.GetOrLoad(context.Background(), "aaa", loader)
+		_, _ = c.GetOrLoad(context.Background(), "aaa", loader)
+
+		stats := c.Stats()
+		require.Equal(t, uint64(1), stats.Hits)
+		require.Equal(t, uint64(1), stats.Misses)
+		require.Equal(t, uint64(1), stats.Sets)
+		require.Equal(t, 1, stats.Size)
+	})
+
+	t.Run("cached load error", func(t *testing.T) {
+		c := NewCache(2, WithNegativeTTL(time.Minute))
+		loader := func(ctx context.Context, key Key) (interface{}, error) {
+			return nil, errors.New("load error")
+		}
+
+		_, _ = c.GetOrLoad(context.Background(), "aaa", loader)
+		_, _ = c.GetOrLoad(context.Background(), "aaa", loader)
+		_, _ = c.Get("aaa")
+
+		// закэшированная ошибка - промах и для GetOrLoad, и для Get
+		stats := c.Stats()
+		require.Equal(t, uint64(0), stats.Hits)
+		require.Equal(t, uint64(3), stats.Misses)
+	})
+
+	t.Run("collector", func(t *testing.T) {
+		c := NewCache(5)
+		_ = c.Set("aaa", 100)
+		_, _ = c.Get("aaa")
+
+		collector := StatsCollector{Namespace: "lru", Cache: c}
+
+		var buf bytes.Buffer
+		_, err := collector.WriteTo(&buf)
+		require.NoError(t, err)
+		require.Contains(t, buf.String(), "# TYPE lru_hits_total counter\nlru_hits_total 1\n")
+		require.Contains(t, buf.String(), "# TYPE lru_size gauge\nlru_size 1\n")
+		require.Contains(t, buf.String(), "lru_capacity 5\n")
+
+		rec := httptest.NewRecorder()
+		collector.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+		require.Equal(t, buf.String(), rec.Body.String())
+	})
+}
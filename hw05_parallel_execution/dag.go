@@ -0,0 +1,248 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrDuplicateTaskID   = errors.New("duplicate task id")
+	ErrUnknownDependency = errors.New("unknown dependency")
+	ErrDependencyCycle   = errors.New("dependency cycle")
+	ErrDependencyFailed  = errors.New("dependency failed")
+	ErrDependencySkipped = errors.New("dependency skipped")
+)
+
+// DAGTask - задача, запускаемая после успешного выполнения всех её зависимостей.
+type DAGTask struct {
+	ID        string
+	DependsOn []string // идентификаторы задач, которые должны выполниться раньше
+	Run       ContextTask
+}
+
+// DAGReport - результаты выполнения графа задач.
+type DAGReport struct {
+	Order   []string              // идентификаторы запущенных задач в порядке запуска
+	Results map[string]TaskResult // результаты по идентификаторам, Index - номер задачи в исходном списке
+}
+
+// узел графа задач.
+type dagNode struct {
+	index      int
+	task       DAGTask
+	pending    int        // количество ещё не выполненных зависимостей
+	dependents []*dagNode // задачи, зависящие от этой
+	resolved   bool       // задача выполнена или пропущена
+}
+
+// RunDAG runs tasks in opts.Workers goroutines, starting each task as soon as all its dependencies succeed.
+// Dependents of a failed task are skipped. Dependency cycles and unknown dependencies are reported before start.
+// Errors limit, cancellation and other options work like in RunContext.
+// Index passed to opts.Weight is the position of the task in the list.
+func RunDAG(ctx context.Context, tasks []DAGTask, opts Options) (DAGReport, error) {
+	nodes, err := buildDAG(tasks)
+	if err != nil {
+		return DAGReport{}, err
+	}
+
+	report := DAGReport{Results: make(map[string]TaskResult, len(tasks))}
+	for _, node := range nodes {
+		report.Results[node.task.ID] = TaskResult{Index: node.index, Skipped: true}
+	}
+
+	var mutex sync.Mutex
+	resolvedCount := 0
+
+	// готовые к запуску задачи, буфер на все задачи, чтобы завершение задачи не блокировалось
+	ready := make(chan *dagNode, len(nodes))
+	started := make([]*dagNode, 0, len(nodes))
+
+	// пометить задачу выполненной, закрыть очередь, когда выполнены все.
+	// Вызывается под блокировкой.
+	resolve := func(node *dagNode) {
+		node.resolved = true
+		resolvedCount++
+		if resolvedCount == len(nodes) {
+			close(ready)
+		}
+	}
+
+	// пропустить зависящие от задачи, сообщив причину, вернуть количество пропущенных.
+	// Вызывается под блокировкой.
+	var skipDependents func(node *dagNode, reason error) int
+	skipDependents = func(node *dagNode, reason error) int {
+		skipped := 0
+		for _, dependent := range node.dependents {
+			if dependent.resolved {
+				continue
+			}
+
+			result := report.Results[dependent.task.ID]
+			result.Err = fmt.Errorf("%w: %s", reason, node.task.ID)
+			report.Results[dependent.task.ID] = result
+
+			resolve(dependent)
+			skipped += 1 + skipDependents(dependent, ErrDependencySkipped)
+		}
+
+		return skipped
+	}
+
+	for _, node := range nodes {
+		if node.pending == 0 {
+			ready <- node
+		}
+	}
+	if len(nodes) == 0 {
+		close(ready)
+	}
+
+	next := func(ctx context.Context) (ContextTask, bool) {
+		select {
+		case node, ok := <-ready:
+			if !ok {
+				return nil, false
+			}
+
+			mutex.Lock()
+			started = append(started, node)
+			mutex.Unlock()
+
+			return node.task.Run, true
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+
+	onResult := func(result TaskResult) int {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		// номер задачи в источнике - порядок запуска
+		node := started[result.Index]
+		result.Index = node.index
+		report.Results[node.task.ID] = result
+
+		resolve(node)
+
+		if result.Err != nil {
+			return skipDependents(node, ErrDependencyFailed)
+		}
+
+		for _, dependent := range node.dependents {
+			dependent.pending--
+			if dependent.pending == 0 && !dependent.resolved {
+				ready <- dependent
+			}
+		}
+
+		return 0
+	}
+
+	if weight := opts.Weight; weight != nil {
+		opts.Weight = func(index int) int64 {
+			mutex.Lock()
+			node := started[index]
+			mutex.Unlock()
+
+			return weight(node.index)
+		}
+	}
+
+	err = executeIterator(ctx, next, len(nodes), opts, onResult)
+
+	// взятые одновременно с остановкой задачи не запускались
+	for _, node := range started {
+		if !report.Results[node.task.ID].Skipped {
+			report.Order = append(report.Order, node.task.ID)
+		}
+	}
+
+	return report, err
+}
+
+// построить граф задач, проверив идентификаторы, зависимости и отсутствие циклов.
+func buildDAG(tasks []DAGTask) ([]*dagNode, error) {
+	nodes := make([]*dagNode, 0, len(tasks))
+	byID := make(map[string]*dagNode, len(tasks))
+
+	for i, task := range tasks {
+		if _, exists := byID[task.ID]; exists {
+			return nil, fmt.Errorf("%w: %q", ErrDuplicateTaskID, task.ID)
+		}
+
+		node := &dagNode{index: i, task: task, pending: len(task.DependsOn)}
+		nodes = append(nodes, node)
+		byID[task.ID] = node
+	}
+
+	for _, node := range nodes {
+		for _, id := range node.task.DependsOn {
+			dependency, exists := byID[id]
+			if !exists {
+				return nil, fmt.Errorf("%w: %q depends on %q", ErrUnknownDependency, node.task.ID, id)
+			}
+			dependency.dependents = append(dependency.dependents, node)
+		}
+	}
+
+	if cycle := findCycle(nodes, byID); cycle != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(cycle, " -> "))
+	}
+
+	return nodes, nil
+}
+
+// найти цикл зависимостей обходом в глубину, вернуть его идентификаторы или nil.
+func findCycle(nodes []*dagNode, byID map[string]*dagNode) []string {
+	const (
+		unvisited = iota
+		inPath
+		visited
+	)
+
+	state := make(map[*dagNode]int, len(nodes))
+	path := make([]string, 0, len(nodes))
+
+	var visit func(node *dagNode) []string
+	visit = func(node *dagNode) []string {
+		state[node] = inPath
+		path = append(path, node.task.ID)
+
+		for _, id := range node.task.DependsOn {
+			dependency := byID[id]
+
+			switch state[dependency] {
+			case inPath:
+				// цикл - от первого вхождения зависимости в путь до конца пути
+				for i, pathID := range path {
+					if pathID == id {
+						return append(append([]string(nil), path[i:]...), id)
+					}
+				}
+			case unvisited:
+				if cycle := visit(dependency); cycle != nil {
+					return cycle
+				}
+			}
+		}
+
+		path = path[:len(path)-1]
+		state[node] = visited
+
+		return nil
+	}
+
+	for _, node := range nodes {
+		if state[node] == unvisited {
+			if cycle := visit(node); cycle != nil {
+				return cycle
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestRunDAG(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	errTask := errors.New("task error")
+
+	// журнал завершения задач
+	type journal struct {
+		mutex    sync.Mutex
+		finished []string
+	}
+
+	task := func(j *journal, id string, sleep time.Duration, err error) ContextTask {
+		return func(ctx context.Context) error {
+			time.Sleep(sleep)
+
+			j.mutex.Lock()
+			j.finished = append(j.finished, id)
+			j.mutex.Unlock()
+
+			return err
+		}
+	}
+
+	// позиция задачи в списке
+	position := func(ids []string, id string) int {
+		for i, v := range ids {
+			if v == id {
+				return i
+			}
+		}
+		return -1
+	}
+
+	t.Run("dependencies run first", func(t *testing.T) {
+		j := &journal{}
+
+		//   a   b
+		//  / \ /
+		// c   d
+		//  \ /
+		//   e
+		tasks := []DAGTask{
+			{ID: "e", DependsOn: []string{"c", "d"}, Run: task(j, "e", 0, nil)},
+			{ID: "c", DependsOn: []string{"a"}, Run: task(j, "c", time.Millisecond*20, nil)},
+			{ID: "d", DependsOn: []string{"a", "b"}, Run: task(j, "d", 0, nil)},
+			{ID: "a", Run: task(j, "a", time.Millisecond*10, nil)},
+			{ID: "b", Run: task(j, "b", 0, nil)},
+		}
+
+		report, err := RunDAG(context.Background(), tasks, Options{Workers: 3, MaxErrors: 1})
+
+		require.NoError(t, err)
+		require.Len(t, report.Order, 5)
+		require.Equal(t, "e", report.Order[4])
+
+		for _, task := range tasks {
+			for _, dependency := range task.DependsOn {
+				require.Less(t, position(j.finished, dependency), position(j.finished, task.ID))
+				require.Less(t, position(report.Order, dependency), position(report.Order, task.ID))
+			}
+
+			result := report.Results[task.ID]
+			require.False(t, result.Skipped)
+			require.Equal(t, task.ID, tasks[result.Index].ID)
+		}
+	})
+
+	t.Run("independent tasks run in parallel", func(t *testing.T) {
+		j := &journal{}
+		tasks := []DAGTask{
+			{ID: "a", Run: task(j, "a", time.Millisecond*50, nil)},
+			{ID: "b", Run: task(j, "b", time.Millisecond*50, nil)},
+			{ID: "c", Run: task(j, "c", time.Millisecond*50, nil)},
+			{ID: "d", DependsOn: []string{"a", "b", "c"}, Run: task(j, "d", 0, nil)},
+		}
+
+		start := time.Now()
+		_, err := RunDAG(context.Background(), tasks, Options{Workers: 3})
+
+		require.NoError(t, err)
+		require.Less(t, int64(time.Since(start)), int64(time.Millisecond*100))
+	})
+
+	t.Run("dependents of failed task skipped", func(t *testing.T) {
+		j := &journal{}
+		tasks := []DAGTask{
+			{ID: "a", Run: task(j, "a", 0, errTask)},
+			{ID: "b", DependsOn: []string{"a"}, Run: task(j, "b", 0, nil)},
+			{ID: "c", DependsOn: []string{"b"}, Run: task(j, "c", 0, nil)},
+			{ID: "d", Run: task(j, "d", time.Millisecond*10, nil)},
+			{ID: "e", DependsOn: []string{"d"}, Run: task(j, "e", 0, nil)},
+		}
+
+		report, err := RunDAG(context.Background(), tasks, Options{Workers: 2})
+
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"a", "d", "e"}, report.Order)
+		require.ElementsMatch(t, []string{"a", "d", "e"}, j.finished)
+
+		require.Equal(t, errTask, report.Results["a"].Err)
+
+		require.True(t, report.Results["b"].Skipped)
+		require.True(t, errors.Is(report.Results["b"].Err, ErrDependencyFailed))
+		require.EqualError(t, report.Results["b"].Err, "dependency failed: a")
+
+		require.True(t, report.Results["c"].Skipped)
+		require.True(t, errors.Is(report.Results["c"].Err, ErrDependencySkipped))
+	})
+
+	t.Run("errors limit", func(t *testing.T) {
+		j := &journal{}
+		tasks := []DAGTask{
+			{ID: "a", Run: task(j, "a", 0, errTask)},
+			{ID: "b", DependsOn: []string{"a"}, Run: task(j, "b", 0, nil)},
+			{ID: "c", Run: task(j, "c", time.Millisecond*10, nil)},
+			{ID: "d", DependsOn: []string{"c"}, Run: task(j, "d", 0, nil)},
+		}
+
+		report, err := RunDAG(context.Background(), tasks, Options{Workers: 1, MaxErrors: 1})
+
+		require.Equal(t, ErrErrorsLimitExceeded, err)
+		require.Equal(t, []string{"a"}, report.Order)
+		require.True(t, report.Results["c"].Skipped)
+		require.NoError(t, report.Results["c"].Err)
+		require.True(t, report.Results["d"].Skipped)
+	})
+
+	t.Run("invalid graph", func(t *testing.T) {
+		noop := func(ctx context.Context) error { return nil }
+
+		_, err := RunDAG(context.Background(), []DAGTask{
+			{ID: "a", DependsOn: []string{"c"}, Run: noop},
+			{ID: "b", DependsOn: []string{"a"}, Run: noop},
+			{ID: "c", DependsOn: []string{"b"}, Run: noop},
+			{ID: "d", Run: noop},
+		}, Options{Workers: 1})
+		require.True(t, errors.Is(err, ErrDependencyCycle))
+		require.EqualError(t, err, "dependency cycle: a -> c -> b -> a")
+
+		_, err = RunDAG(context.Background(), []DAGTask{
+			{ID: "a", DependsOn: []string{"a"}, Run: noop},
+		}, Options{Workers: 1})
+		require.True(t, errors.Is(err, ErrDependencyCycle))
+
+		_, err = RunDAG(context.Background(), []DAGTask{
+			{ID: "a", DependsOn: []string{"x"}, Run: noop},
+		}, Options{Workers: 1})
+		require.True(t, errors.Is(err, ErrUnknownDependency))
+
+		_, err = RunDAG(context.Background(), []DAGTask{
+			{ID: "a", Run: noop},
+			{ID: "a", Run: noop},
+		}, Options{Workers: 1})
+		require.True(t, errors.Is(err, ErrDuplicateTaskID))
+	})
+
+	t.Run("weight gets position in list", func(t *testing.T) {
+		j := &journal{}
+
+		var mutex sync.Mutex
+		var weighted []int
+
+		// запускаются в порядке a, b
+		tasks := []DAGTask{
+			{ID: "b", DependsOn: []string{"a"}, Run: task(j, "b", 0, nil)},
+			{ID: "a", Run: task(j, "a", 0, nil)},
+		}
+
+		_, err := RunDAG(context.Background(), tasks, Options{
+			Workers:  1,
+			Capacity: 1,
+			Weight: func(index int) int64 {
+				mutex.Lock()
+				weighted = append(weighted, index)
+				mutex.Unlock()
+				return 1
+			},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 0}, weighted)
+	})
+
+	t.Run("empty graph", func(t *testing.T) {
+		report, err := RunDAG(context.Background(), nil, Options{Workers: 1})
+		require.NoError(t, err)
+		require.Empty(t, report.Order)
+	})
+}
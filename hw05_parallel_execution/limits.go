@@ -0,0 +1,213 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrWeightExceedsCapacity = errors.New("task weight exceeds capacity")
+
+// RateLimit - ограничение частоты запуска задач по алгоритму token bucket.
+type RateLimit struct {
+	PerSecond float64 // запусков в секунду, <= 0 - без ограничения
+	Burst     int     // запусков подряд без ожидания, < 1 - один
+}
+
+// tokenBucket выдаёт разрешения на запуск с частотой rate, накапливая до burst разрешений.
+type tokenBucket struct {
+	mutex  sync.Mutex
+	clock  Clock
+	rate   float64 // разрешений в секунду
+	burst  float64
+	tokens float64 // доступные разрешения, отрицательное значение - очередь ожидающих
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit, clock Clock) *tokenBucket {
+	if limit.PerSecond <= 0 {
+		return nil
+	}
+
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket{
+		clock:  clock,
+		rate:   limit.PerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   clock.Now(),
+	}
+}
+
+// дождаться разрешения на запуск.
+func (bucket *tokenBucket) wait(ctx context.Context) error {
+	bucket.mutex.Lock()
+
+	now := bucket.clock.Now()
+	bucket.tokens += now.Sub(bucket.last).Seconds() * bucket.rate
+	if bucket.tokens > bucket.burst {
+		bucket.tokens = bucket.burst
+	}
+	bucket.last = now
+
+	// разрешение резервируется сразу, ожидание - время до его появления
+	bucket.tokens--
+	delay := time.Duration(-bucket.tokens / bucket.rate * float64(time.Second))
+
+	bucket.mutex.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	select {
+	case <-bucket.clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		// возвращаем неиспользованное разрешение
+		bucket.mutex.Lock()
+		bucket.tokens++
+		bucket.mutex.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+// weightedSemaphore ограничивает суммарный вес одновременно выполняемых задач.
+// Ожидающие обслуживаются по очереди, тяжёлая задача не голодает из-за лёгких.
+type weightedSemaphore struct {
+	mutex    sync.Mutex
+	capacity int64
+	used     int64
+	waiters  []*semaphoreWaiter
+}
+
+type semaphoreWaiter struct {
+	weight int64
+	ready  chan struct{} // закрывается при выделении веса
+}
+
+func newWeightedSemaphore(capacity int64) *weightedSemaphore {
+	if capacity <= 0 {
+		return nil
+	}
+
+	return &weightedSemaphore{capacity: capacity}
+}
+
+func (sem *weightedSemaphore) acquire(ctx context.Context, weight int64) error {
+	if weight > sem.capacity {
+		return ErrWeightExceedsCapacity
+	}
+
+	sem.mutex.Lock()
+
+	if len(sem.waiters) == 0 && sem.used+weight <= sem.capacity {
+		sem.used += weight
+		sem.mutex.Unlock()
+		return nil
+	}
+
+	waiter := &semaphoreWaiter{weight: weight, ready: make(chan struct{})}
+	sem.waiters = append(sem.waiters, waiter)
+
+	sem.mutex.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	sem.mutex.Lock()
+	defer sem.mutex.Unlock()
+
+	select {
+	case <-waiter.ready:
+		// вес выделен одновременно с отменой - возвращаем его
+		sem.used -= weight
+	default:
+		for i, w := range sem.waiters {
+			if w == waiter {
+				sem.waiters = append(sem.waiters[:i], sem.waiters[i+1:]...)
+				break
+			}
+		}
+	}
+
+	// ушедший из начала очереди мог задерживать следующих
+	sem.notify()
+
+	return ctx.Err()
+}
+
+func (sem *weightedSemaphore) release(weight int64) {
+	sem.mutex.Lock()
+	defer sem.mutex.Unlock()
+
+	sem.used -= weight
+	sem.notify()
+}
+
+// выделить вес ожидающим из начала очереди. Вызывается под блокировкой.
+func (sem *weightedSemaphore) notify() {
+	for len(sem.waiters) > 0 {
+		waiter := sem.waiters[0]
+		if sem.used+waiter.weight > sem.capacity {
+			return
+		}
+
+		sem.used += waiter.weight
+		close(waiter.ready)
+		sem.waiters = sem.waiters[1:]
+	}
+}
+
+// ограничения запуска задач, общие для всех воркеров.
+type limits struct {
+	bucket    *tokenBucket
+	semaphore *weightedSemaphore
+	weight    func(index int) int64
+}
+
+func newLimits(opts Options, clock Clock) *limits {
+	return &limits{
+		bucket:    newTokenBucket(opts.RateLimit, clock),
+		semaphore: newWeightedSemaphore(opts.Capacity),
+		weight:    opts.Weight,
+	}
+}
+
+// дождаться разрешения на запуск задачи index, вернуть функцию освобождения.
+func (lim *limits) acquire(ctx context.Context, index int) (func(), error) {
+	if lim.bucket != nil {
+		if err := lim.bucket.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if lim.semaphore == nil {
+		return func() {}, nil
+	}
+
+	weight := int64(1)
+	if lim.weight != nil {
+		weight = lim.weight(index)
+	}
+	if weight < 0 {
+		weight = 0
+	}
+
+	if err := lim.semaphore.acquire(ctx, weight); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		lim.semaphore.release(weight)
+	}, nil
+}
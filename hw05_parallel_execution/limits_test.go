@@ -0,0 +1,142 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestRunLimits(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	noop := func(ctx context.Context) error { return nil }
+
+	t.Run("rate limit", func(t *testing.T) {
+		clock := &fakeClock{}
+		tasks := []ContextTask{noop, noop, noop, noop, noop}
+
+		err := RunContext(context.Background(), tasks, Options{
+			Workers:   1,
+			Clock:     clock,
+			RateLimit: RateLimit{PerSecond: 10, Burst: 2},
+		})
+
+		require.NoError(t, err)
+		// первые Burst задач запускаются сразу, остальные - раз в 100ms
+		require.Equal(t, []time.Duration{
+			time.Millisecond * 100,
+			time.Millisecond * 100,
+			time.Millisecond * 100,
+		}, clock.Sleeps())
+	})
+
+	t.Run("rate limit with real clock", func(t *testing.T) {
+		tasks := make([]ContextTask, 0, 10)
+		for i := 0; i < 10; i++ {
+			tasks = append(tasks, noop)
+		}
+
+		start := time.Now()
+		err := RunContext(context.Background(), tasks, Options{
+			Workers:   5,
+			RateLimit: RateLimit{PerSecond: 100, Burst: 5},
+		})
+		elapsed := time.Since(start)
+
+		require.NoError(t, err)
+		// 5 задач сразу, ещё 5 - по одной за 10ms
+		require.GreaterOrEqual(t, int64(elapsed), int64(time.Millisecond*40))
+		require.Less(t, int64(elapsed), int64(time.Second))
+	})
+
+	t.Run("rate limit wait interrupted", func(t *testing.T) {
+		tasks := []ContextTask{noop, noop, noop}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer cancel()
+
+		results, err := RunResults(ctx, tasks, Options{
+			Workers:   3,
+			RateLimit: RateLimit{PerSecond: 0.1},
+		})
+
+		require.True(t, errors.Is(err, context.DeadlineExceeded))
+		require.False(t, results[0].Skipped)
+		require.True(t, results[1].Skipped)
+		require.True(t, results[2].Skipped)
+		require.NoError(t, results[1].Err)
+	})
+
+	t.Run("weighted concurrency", func(t *testing.T) {
+		weights := []int64{2, 1, 3, 1, 2, 2, 1, 3, 1, 1}
+		capacity := int64(3)
+
+		var runningWeight, maxRunningWeight int64
+
+		tasks := make([]ContextTask, 0, len(weights))
+		for _, weight := range weights {
+			weight := weight
+			tasks = append(tasks, func(ctx context.Context) error {
+				running := atomic.AddInt64(&runningWeight, weight)
+				defer atomic.AddInt64(&runningWeight, -weight)
+
+				for {
+					maxRunning := atomic.LoadInt64(&maxRunningWeight)
+					if running <= maxRunning || atomic.CompareAndSwapInt64(&maxRunningWeight, maxRunning, running) {
+						break
+					}
+				}
+
+				time.Sleep(time.Millisecond * 10)
+				return nil
+			})
+		}
+
+		err := RunContext(context.Background(), tasks, Options{
+			Workers:  len(weights),
+			Capacity: capacity,
+			Weight: func(index int) int64 {
+				return weights[index]
+			},
+		})
+
+		require.NoError(t, err)
+		require.LessOrEqual(t, maxRunningWeight, capacity)
+		require.Equal(t, int64(0), runningWeight)
+	})
+
+	t.Run("weight exceeds capacity", func(t *testing.T) {
+		results, err := RunResults(context.Background(), []ContextTask{noop, noop}, Options{
+			Workers:   2,
+			MaxErrors: 1,
+			Capacity:  2,
+			Weight: func(index int) int64 {
+				return int64(index + 2)
+			},
+		})
+
+		require.True(t, errors.Is(err, ErrWeightExceedsCapacity))
+		require.NoError(t, results[0].Err)
+		require.Equal(t, ErrWeightExceedsCapacity, results[1].Err)
+		require.Equal(t, 0, results[1].Attempts)
+	})
+
+	t.Run("semaphore wait interrupted", func(t *testing.T) {
+		sem := newWeightedSemaphore(2)
+		require.NoError(t, sem.acquire(context.Background(), 2))
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+		defer cancel()
+
+		require.Equal(t, context.DeadlineExceeded, sem.acquire(ctx, 1))
+
+		// ушедший из очереди не держит вес
+		sem.release(2)
+		require.NoError(t, sem.acquire(context.Background(), 2))
+	})
+}
@@ -0,0 +1,34 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError - паника в задаче, перехваченная воркером.
+// Считается ошибкой задачи: учитывается в лимите ошибок и повторяется по RetryPolicy.
+type PanicError struct {
+	Value interface{} // значение, переданное в panic
+	Stack []byte      // стек горутины в момент паники
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("task panicked: %v", e.Value)
+}
+
+// Unwrap возвращает значение паники, если это ошибка.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+
+	return nil
+}
+
+// перехватить панику задачи и вернуть её как *PanicError через err.
+// Вызывается через defer.
+func recoverPanic(err *error) {
+	if value := recover(); value != nil {
+		*err = &PanicError{Value: value, Stack: debug.Stack()}
+	}
+}
@@ -0,0 +1,96 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestPanic(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("other tasks keep running", func(t *testing.T) {
+		var runTasksCount int32
+
+		tasks := make([]Task, 0, 20)
+		for i := 0; i < 20; i++ {
+			i := i
+			tasks = append(tasks, func() error {
+				atomic.AddInt32(&runTasksCount, 1)
+				if i%4 == 0 {
+					panic("boom")
+				}
+				return nil
+			})
+		}
+
+		require.NoError(t, Run(tasks, 5, 0))
+		require.Equal(t, int32(20), runTasksCount)
+	})
+
+	t.Run("panics count toward errors limit", func(t *testing.T) {
+		var runTasksCount int32
+
+		tasks := make([]Task, 0, 50)
+		for i := 0; i < 50; i++ {
+			tasks = append(tasks, func() error {
+				atomic.AddInt32(&runTasksCount, 1)
+				panic("boom")
+			})
+		}
+
+		workersCount, maxErrorsCount := 5, 3
+		err := Run(tasks, workersCount, maxErrorsCount)
+
+		require.Equal(t, ErrErrorsLimitExceeded, err)
+		require.LessOrEqual(t, runTasksCount, int32(workersCount+maxErrorsCount))
+	})
+
+	t.Run("panic error", func(t *testing.T) {
+		tasks := []ContextTask{
+			func(ctx context.Context) error { return nil },
+			func(ctx context.Context) error { panic(io.ErrUnexpectedEOF) },
+			func(ctx context.Context) error { panic("boom") },
+		}
+
+		results, err := RunResults(context.Background(), tasks, Options{Workers: 2})
+		require.Error(t, err)
+		require.NoError(t, results[0].Err)
+
+		var panicErr *PanicError
+		require.True(t, errors.As(results[1].Err, &panicErr))
+		require.True(t, errors.Is(results[1].Err, io.ErrUnexpectedEOF))
+		require.Contains(t, string(panicErr.Stack), "panic_test.go")
+
+		require.True(t, errors.As(results[2].Err, &panicErr))
+		require.Equal(t, "boom", panicErr.Value)
+		require.EqualError(t, panicErr, "task panicked: boom")
+		require.Nil(t, panicErr.Unwrap())
+
+		require.True(t, errors.As(err, &panicErr))
+	})
+
+	t.Run("panic is retried", func(t *testing.T) {
+		var attempts int32
+		task := func(ctx context.Context) error {
+			if atomic.AddInt32(&attempts, 1) < 3 {
+				panic("boom")
+			}
+			return nil
+		}
+
+		err := RunContext(context.Background(), []ContextTask{task}, Options{
+			Workers: 1,
+			Retry:   RetryPolicy{MaxAttempts: 3},
+			Clock:   &fakeClock{},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, int32(3), attempts)
+	})
+}
@@ -0,0 +1,41 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"sort"
+)
+
+// PriorityTask - задача с приоритетом, задачи с большим приоритетом запускаются раньше.
+type PriorityTask struct {
+	Priority int
+	Run      ContextTask
+}
+
+// RunPriority runs tasks like RunContext, giving every free worker the task with the highest priority left.
+// Tasks with equal priorities are started in the order of the list.
+// Index passed to opts.Weight is the position of the task in the list.
+func RunPriority(ctx context.Context, tasks []PriorityTask, opts Options) error {
+	// номера задач в порядке запуска
+	order := make([]int, len(tasks))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return tasks[order[i]].Priority > tasks[order[j]].Priority
+	})
+
+	sorted := make([]ContextTask, len(tasks))
+	for i, index := range order {
+		sorted[i] = tasks[index].Run
+	}
+
+	if weight := opts.Weight; weight != nil {
+		opts.Weight = func(index int) int64 {
+			return weight(order[index])
+		}
+	}
+
+	_, err := execute(ctx, sorted, opts)
+
+	return err
+}
@@ -0,0 +1,95 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestRunPriority(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	t.Run("higher priority runs first", func(t *testing.T) {
+		var mutex sync.Mutex
+		var started []int
+
+		task := func(id int) ContextTask {
+			return func(ctx context.Context) error {
+				mutex.Lock()
+				started = append(started, id)
+				mutex.Unlock()
+				return nil
+			}
+		}
+
+		tasks := []PriorityTask{
+			{Priority: 1, Run: task(0)},
+			{Priority: 5, Run: task(1)},
+			{Priority: 1, Run: task(2)},
+			{Priority: 10, Run: task(3)},
+			{Priority: -1, Run: task(4)},
+			{Priority: 5, Run: task(5)},
+		}
+
+		err := RunPriority(context.Background(), tasks, Options{Workers: 1})
+
+		require.NoError(t, err)
+		require.Equal(t, []int{3, 1, 5, 0, 2, 4}, started)
+	})
+
+	t.Run("errors limit", func(t *testing.T) {
+		errTask := errors.New("task error")
+		var mutex sync.Mutex
+		var started []int
+
+		task := func(id int, err error) ContextTask {
+			return func(ctx context.Context) error {
+				mutex.Lock()
+				started = append(started, id)
+				mutex.Unlock()
+				return err
+			}
+		}
+
+		tasks := []PriorityTask{
+			{Priority: 0, Run: task(0, nil)},
+			{Priority: 2, Run: task(1, errTask)},
+			{Priority: 1, Run: task(2, nil)},
+		}
+
+		err := RunPriority(context.Background(), tasks, Options{Workers: 1, MaxErrors: 1})
+
+		require.Equal(t, ErrErrorsLimitExceeded, err)
+		require.Equal(t, []int{1}, started)
+	})
+
+	t.Run("weight gets position in list", func(t *testing.T) {
+		noop := func(ctx context.Context) error { return nil }
+
+		var mutex sync.Mutex
+		var weighted []int
+
+		tasks := []PriorityTask{
+			{Priority: 0, Run: noop},
+			{Priority: 1, Run: noop},
+		}
+
+		err := RunPriority(context.Background(), tasks, Options{
+			Workers:  1,
+			Capacity: 1,
+			Weight: func(index int) int64 {
+				mutex.Lock()
+				weighted = append(weighted, index)
+				mutex.Unlock()
+				return 1
+			},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 0}, weighted)
+	})
+}
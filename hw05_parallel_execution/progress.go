@@ -0,0 +1,113 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"sync"
+	"time"
+)
+
+// Progress - снимок хода выполнения задач.
+type Progress struct {
+	Total     int           // всего задач, -1 - неизвестно (задачи из итератора)
+	Started   int           // запущено, получив разрешение лимитов, или отклонено лимитами
+	Completed int           // выполнено успешно
+	Failed    int           // выполнено с ошибкой, в т.ч. отклонено лимитами
+	Skipped   int           // не будет запущено из-за упавших зависимостей (RunDAG)
+	Remaining int           // не выполнено и не пропущено, -1 - неизвестно
+	Elapsed   time.Duration // времени прошло с начала выполнения
+	ETA       time.Duration // оценка оставшегося времени, 0 - оценки нет
+}
+
+// ProgressToChan возвращает обработчик для Options.OnProgress, отправляющий снимки в канал.
+// Канал должен быть буферизованным: если получатель не успевает, самый старый снимок
+// в буфере заменяется новым, поэтому воркеры не ждут, а последний снимок всегда доставляется.
+func ProgressToChan(ch chan Progress) func(Progress) {
+	if cap(ch) == 0 {
+		panic("ProgressToChan: channel must be buffered")
+	}
+
+	// снимки сообщаются по одному, поэтому в канал пишет только этот обработчик
+	return func(progress Progress) {
+		for {
+			select {
+			case ch <- progress:
+				return
+			default:
+			}
+
+			select {
+			case <-ch:
+			default:
+			}
+		}
+	}
+}
+
+// progressTracker считает задачи и сообщает снимки обработчику по одному.
+type progressTracker struct {
+	mutex      sync.Mutex
+	clock      Clock
+	start      time.Time
+	progress   Progress
+	onProgress func(Progress)
+}
+
+func newProgressTracker(total int, clock Clock, onProgress func(Progress)) *progressTracker {
+	remaining := total
+	if total < 0 {
+		total = -1
+		remaining = -1
+	}
+
+	return &progressTracker{
+		clock:      clock,
+		start:      clock.Now(),
+		progress:   Progress{Total: total, Remaining: remaining},
+		onProgress: onProgress,
+	}
+}
+
+// изменить счётчики и сообщить снимок.
+func (tracker *progressTracker) update(change func(progress *Progress)) {
+	if tracker.onProgress == nil {
+		return
+	}
+
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	progress := &tracker.progress
+	change(progress)
+
+	progress.Elapsed = tracker.clock.Now().Sub(tracker.start)
+
+	finished := progress.Completed + progress.Failed
+	if progress.Total >= 0 {
+		progress.Remaining = progress.Total - finished - progress.Skipped
+	}
+
+	// оставшиеся задачи выполняются в среднем так же, как выполненные
+	progress.ETA = 0
+	if finished > 0 && progress.Remaining > 0 {
+		progress.ETA = progress.Elapsed / time.Duration(finished) * time.Duration(progress.Remaining)
+	}
+
+	tracker.onProgress(*progress)
+}
+
+func (tracker *progressTracker) started() {
+	tracker.update(func(progress *Progress) {
+		progress.Started++
+	})
+}
+
+// задача выполнена, из-за её результата не будут запущены ещё skipped задач.
+func (tracker *progressTracker) finished(err error, skipped int) {
+	tracker.update(func(progress *Progress) {
+		if err == nil {
+			progress.Completed++
+		} else {
+			progress.Failed++
+		}
+		progress.Skipped += skipped
+	})
+}
@@ -0,0 +1,202 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestProgress(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	errTask := errors.New("task error")
+
+	t.Run("counts", func(t *testing.T) {
+		var mutex sync.Mutex
+		var snapshots []Progress
+
+		tasks := make([]ContextTask, 0, 10)
+		for i := 0; i < 10; i++ {
+			var err error
+			if i%5 == 0 {
+				err = errTask
+			}
+			tasks = append(tasks, func(ctx context.Context) error {
+				return err
+			})
+		}
+
+		err := RunContext(context.Background(), tasks, Options{
+			Workers: 3,
+			OnProgress: func(progress Progress) {
+				mutex.Lock()
+				snapshots = append(snapshots, progress)
+				mutex.Unlock()
+			},
+		})
+		require.NoError(t, err)
+
+		// запуск и завершение каждой задачи
+		require.Len(t, snapshots, 20)
+
+		for _, progress := range snapshots {
+			require.Equal(t, 10, progress.Total)
+			require.Equal(t, progress.Total-progress.Completed-progress.Failed-progress.Skipped, progress.Remaining)
+			require.LessOrEqual(t, progress.Completed+progress.Failed, progress.Started)
+		}
+
+		last := snapshots[len(snapshots)-1]
+		last.Elapsed, last.ETA = 0, 0
+		require.Equal(t, Progress{Total: 10, Started: 10, Completed: 8, Failed: 2}, last)
+	})
+
+	t.Run("started after permit", func(t *testing.T) {
+		var mutex sync.Mutex
+		var snapshots []Progress
+
+		task := func(ctx context.Context) error {
+			time.Sleep(time.Millisecond * 10)
+			return nil
+		}
+
+		err := RunContext(context.Background(), []ContextTask{task, task, task, task}, Options{
+			Workers:  3,
+			Capacity: 1,
+			OnProgress: func(progress Progress) {
+				mutex.Lock()
+				snapshots = append(snapshots, progress)
+				mutex.Unlock()
+			},
+		})
+		require.NoError(t, err)
+
+		// ждущие разрешения воркеры задачи не запускают
+		for _, progress := range snapshots {
+			require.LessOrEqual(t, progress.Started-progress.Completed-progress.Failed, 1)
+		}
+	})
+
+	t.Run("eta", func(t *testing.T) {
+		clock := &fakeClock{now: time.Now()}
+
+		// каждая задача "выполняется" секунду
+		task := func(ctx context.Context) error {
+			<-clock.After(time.Second)
+			return nil
+		}
+
+		var snapshots []Progress
+		err := RunContext(context.Background(), []ContextTask{task, task, task, task}, Options{
+			Workers: 1,
+			Clock:   clock,
+			OnProgress: func(progress Progress) {
+				snapshots = append(snapshots, progress)
+			},
+		})
+		require.NoError(t, err)
+
+		// снимок после завершения первой задачи
+		require.Equal(t, 1, snapshots[1].Completed)
+		require.Equal(t, time.Second, snapshots[1].Elapsed)
+		require.Equal(t, time.Second*3, snapshots[1].ETA)
+
+		last := snapshots[len(snapshots)-1]
+		require.Equal(t, time.Second*4, last.Elapsed)
+		require.Zero(t, last.ETA)
+		require.Zero(t, last.Remaining)
+	})
+
+	t.Run("unknown total", func(t *testing.T) {
+		tasks := make(chan ContextTask, 3)
+		for i := 0; i < 3; i++ {
+			tasks <- func(ctx context.Context) error { return nil }
+		}
+		close(tasks)
+
+		progressCh := make(chan Progress, 10)
+		err := RunChan(context.Background(), tasks, Options{Workers: 1, OnProgress: ProgressToChan(progressCh)})
+		require.NoError(t, err)
+		close(progressCh)
+
+		var last Progress
+		for progress := range progressCh {
+			require.Equal(t, -1, progress.Total)
+			require.Equal(t, -1, progress.Remaining)
+			require.Zero(t, progress.ETA)
+			last = progress
+		}
+		require.Equal(t, 3, last.Completed)
+	})
+
+	t.Run("rejected by limits", func(t *testing.T) {
+		var snapshots []Progress
+
+		noop := func(ctx context.Context) error { return nil }
+		err := RunContext(context.Background(), []ContextTask{noop, noop}, Options{
+			Workers:  1,
+			Capacity: 1,
+			Weight: func(index int) int64 {
+				return int64(index + 1)
+			},
+			OnProgress: func(progress Progress) {
+				snapshots = append(snapshots, progress)
+			},
+		})
+		require.NoError(t, err)
+
+		for _, progress := range snapshots {
+			require.LessOrEqual(t, progress.Completed+progress.Failed, progress.Started)
+		}
+
+		last := snapshots[len(snapshots)-1]
+		last.Elapsed, last.ETA = 0, 0
+		require.Equal(t, Progress{Total: 2, Started: 2, Completed: 1, Failed: 1}, last)
+	})
+
+	t.Run("skipped by dag", func(t *testing.T) {
+		progressCh := make(chan Progress, 1)
+
+		noop := func(ctx context.Context) error { return nil }
+		_, err := RunDAG(context.Background(), []DAGTask{
+			{ID: "a", Run: func(ctx context.Context) error { return errTask }},
+			{ID: "b", DependsOn: []string{"a"}, Run: noop},
+			{ID: "c", DependsOn: []string{"b"}, Run: noop},
+			{ID: "d", Run: noop},
+		}, Options{Workers: 1, OnProgress: ProgressToChan(progressCh)})
+		require.NoError(t, err)
+
+		last := <-progressCh
+		last.Elapsed, last.ETA = 0, 0
+		require.Equal(t, Progress{Total: 4, Started: 2, Completed: 1, Failed: 1, Skipped: 2}, last)
+	})
+
+	t.Run("slow receiver gets final snapshot", func(t *testing.T) {
+		progressCh := make(chan Progress, 1)
+		tasks := []ContextTask{
+			func(ctx context.Context) error { return nil },
+			func(ctx context.Context) error { return nil },
+		}
+
+		require.NoError(t, RunContext(context.Background(), tasks, Options{
+			Workers:    2,
+			OnProgress: ProgressToChan(progressCh),
+		}))
+
+		// промежуточные снимки вытеснены последним
+		require.Len(t, progressCh, 1)
+		last := <-progressCh
+		require.Equal(t, 2, last.Completed)
+		require.Zero(t, last.Remaining)
+	})
+
+	t.Run("unbuffered channel", func(t *testing.T) {
+		require.Panics(t, func() {
+			ProgressToChan(make(chan Progress))
+		})
+	})
+}
@@ -0,0 +1,113 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TaskResult - результат выполнения одной задачи.
+type TaskResult struct {
+	Index    int           // номер задачи в исходном списке
+	Duration time.Duration // время выполнения, включая повторы
+	Attempts int           // количество попыток
+	Err      error         // ошибка, которую вернула задача
+	Skipped  bool          // задача не запускалась из-за остановки выполнения
+}
+
+// TaskError - ошибка задачи с её номером.
+type TaskError struct {
+	Index int
+	Err   error
+}
+
+func (e *TaskError) Error() string {
+	return fmt.Sprintf("task %d: %s", e.Index, e.Err)
+}
+
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// TasksError объединяет ошибки всех упавших задач и причину остановки выполнения.
+// errors.Is и errors.As проверяют причину и ошибку каждой задачи.
+type TasksError struct {
+	Reason error        // ErrErrorsLimitExceeded, ошибка контекста или nil, если выполнение не прерывалось
+	Errors []*TaskError // ошибки задач в порядке их номеров
+}
+
+func (e *TasksError) Error() string {
+	var builder strings.Builder
+
+	if e.Reason != nil {
+		builder.WriteString(e.Reason.Error())
+	} else {
+		builder.WriteString("tasks failed")
+	}
+
+	fmt.Fprintf(&builder, " (%d errors)", len(e.Errors))
+
+	for i, taskErr := range e.Errors {
+		if i == 0 {
+			builder.WriteString(": ")
+		} else {
+			builder.WriteString("; ")
+		}
+		builder.WriteString(taskErr.Error())
+	}
+
+	return builder.String()
+}
+
+func (e *TasksError) Is(target error) bool {
+	if e.Reason != nil && errors.Is(e.Reason, target) {
+		return true
+	}
+
+	for _, taskErr := range e.Errors {
+		if errors.Is(taskErr, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *TasksError) As(target interface{}) bool {
+	if e.Reason != nil && errors.As(e.Reason, target) {
+		return true
+	}
+
+	for _, taskErr := range e.Errors {
+		if errors.As(taskErr, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// RunResults runs tasks like RunContext and returns result of every task.
+// The error is *TasksError if any task failed or the run was stopped, nil otherwise.
+func RunResults(ctx context.Context, tasks []ContextTask, opts Options) ([]TaskResult, error) {
+	results, reason := execute(ctx, tasks, opts)
+	if results == nil && reason != nil {
+		return nil, reason
+	}
+
+	var taskErrors []*TaskError
+
+	for _, result := range results {
+		if result.Err != nil {
+			taskErrors = append(taskErrors, &TaskError{Index: result.Index, Err: result.Err})
+		}
+	}
+
+	if reason == nil && len(taskErrors) == 0 {
+		return results, nil
+	}
+
+	return results, &TasksError{Reason: reason, Errors: taskErrors}
+}
@@ -0,0 +1,107 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestRunResults(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	errTask := errors.New("task error")
+
+	t.Run("all succeeded", func(t *testing.T) {
+		tasks := []ContextTask{
+			func(ctx context.Context) error {
+				time.Sleep(time.Millisecond * 20)
+				return nil
+			},
+			func(ctx context.Context) error { return nil },
+		}
+
+		results, err := RunResults(context.Background(), tasks, Options{Workers: 2, MaxErrors: 1})
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		for i, result := range results {
+			require.Equal(t, i, result.Index)
+			require.False(t, result.Skipped)
+			require.NoError(t, result.Err)
+		}
+		require.GreaterOrEqual(t, int64(results[0].Duration), int64(time.Millisecond*20))
+	})
+
+	t.Run("errors limit", func(t *testing.T) {
+		pathErr := &os.PathError{Op: "open", Path: "/nowhere", Err: os.ErrNotExist}
+
+		tasks := []ContextTask{
+			func(ctx context.Context) error { return nil },
+			func(ctx context.Context) error { return errTask },
+			func(ctx context.Context) error { return pathErr },
+			func(ctx context.Context) error { return nil },
+		}
+
+		// один воркер выполняет задачи по порядку
+		results, err := RunResults(context.Background(), tasks, Options{Workers: 1, MaxErrors: 2})
+
+		require.True(t, errors.Is(err, ErrErrorsLimitExceeded))
+		require.True(t, errors.Is(err, errTask))
+		require.True(t, errors.Is(err, os.ErrNotExist))
+
+		var asPathErr *os.PathError
+		require.True(t, errors.As(err, &asPathErr))
+		require.Equal(t, "/nowhere", asPathErr.Path)
+
+		var tasksErr *TasksError
+		require.True(t, errors.As(err, &tasksErr))
+		require.Len(t, tasksErr.Errors, 2)
+		require.Equal(t, 1, tasksErr.Errors[0].Index)
+		require.Equal(t, 2, tasksErr.Errors[1].Index)
+		require.Equal(t,
+			"errors limit exceeded (2 errors): task 1: task error; task 2: open /nowhere: file does not exist",
+			err.Error())
+
+		require.False(t, results[0].Skipped)
+		require.NoError(t, results[0].Err)
+		require.Equal(t, errTask, results[1].Err)
+		require.Equal(t, pathErr, results[2].Err)
+		require.True(t, results[3].Skipped)
+	})
+
+	t.Run("errors ignored", func(t *testing.T) {
+		tasks := []ContextTask{
+			func(ctx context.Context) error { return errTask },
+			func(ctx context.Context) error { return nil },
+		}
+
+		results, err := RunResults(context.Background(), tasks, Options{Workers: 1})
+
+		var tasksErr *TasksError
+		require.True(t, errors.As(err, &tasksErr))
+		require.Nil(t, tasksErr.Reason)
+		require.False(t, errors.Is(err, ErrErrorsLimitExceeded))
+		require.True(t, errors.Is(err, errTask))
+		require.False(t, results[1].Skipped)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		results, err := RunResults(ctx, []ContextTask{func(ctx context.Context) error { return nil }}, Options{Workers: 1})
+		require.True(t, errors.Is(err, context.Canceled))
+		require.True(t, results[0].Skipped)
+	})
+
+	t.Run("no workers", func(t *testing.T) {
+		results, err := RunResults(context.Background(), nil, Options{})
+		require.Equal(t, ErrNoWorkers, err)
+		require.Nil(t, results)
+	})
+}
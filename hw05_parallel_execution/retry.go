@@ -0,0 +1,99 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Clock - источник времени, подменяется в тестах.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// RetryPolicy - правила повтора упавших задач.
+// В лимит ошибок засчитывается только задача, исчерпавшая попытки.
+type RetryPolicy struct {
+	MaxAttempts    int           // максимум попыток, <= 1 - без повторов
+	InitialBackoff time.Duration // пауза перед первым повтором
+	MaxBackoff     time.Duration // ограничение паузы, 0 - без ограничения
+	Multiplier     float64       // рост паузы с каждым повтором, <= 1 - в 2 раза
+	Jitter         float64       // доля случайного отклонения паузы, от 0 до 1
+
+	// Retryable решает, стоит ли повторять задачу после ошибки, nil - повторять при любой ошибке
+	Retryable func(err error) bool
+}
+
+// пауза перед повтором номер retry, начиная с 1.
+func (policy RetryPolicy) backoff(retry int) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier <= 1 {
+		multiplier = 2
+	}
+
+	backoff := float64(policy.InitialBackoff) * math.Pow(multiplier, float64(retry-1))
+
+	if policy.Jitter > 0 {
+		backoff *= 1 + policy.Jitter*(2*rand.Float64()-1) //nolint:gosec
+	}
+
+	// без ограничения пауза всё равно не превышает максимальную длительность
+	limit := policy.MaxBackoff
+	if limit <= 0 {
+		limit = math.MaxInt64
+	}
+
+	// сравнение до преобразования: большие значения не помещаются в time.Duration
+	if backoff >= float64(limit) {
+		return limit
+	}
+
+	return time.Duration(backoff)
+}
+
+func (policy RetryPolicy) retryable(err error) bool {
+	return policy.Retryable == nil || policy.Retryable(err)
+}
+
+// выполнить задачу с повторами, вернуть количество попыток и последнюю ошибку.
+// onStart вызывается, когда задача получила разрешение на первый запуск.
+// Ноль попыток - задача не запускалась, не дождавшись разрешения на запуск.
+func (r *runner) runWithRetry(ctx context.Context, index int, task ContextTask, onStart func()) (int, error) {
+	retry := r.opts.Retry
+
+	for attempt := 1; ; attempt++ {
+		release, err := r.limits.acquire(ctx, index)
+		if err != nil {
+			return attempt - 1, err
+		}
+
+		if attempt == 1 {
+			onStart()
+		}
+
+		err = runTask(ctx, task, r.opts.TaskTimeout)
+		release()
+
+		if err == nil || attempt >= retry.MaxAttempts || !retry.retryable(err) {
+			return attempt, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return attempt, err
+		case <-r.clock.After(retry.backoff(attempt)):
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+// fakeClock не ждёт: After срабатывает сразу, сдвигая время и запоминая запрошенные паузы.
+type fakeClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	return clock.now
+}
+
+func (clock *fakeClock) After(d time.Duration) <-chan time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	clock.now = clock.now.Add(d)
+	clock.sleeps = append(clock.sleeps, d)
+
+	ch := make(chan time.Time, 1)
+	ch <- clock.now
+
+	return ch
+}
+
+func (clock *fakeClock) Sleeps() []time.Duration {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	return append([]time.Duration(nil), clock.sleeps...)
+}
+
+func TestRunRetry(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	errTemporary := errors.New("temporary error")
+	errPermanent := errors.New("permanent error")
+
+	// задача падает failsCount раз, затем выполняется успешно
+	flaky := func(failsCount int32, err error) (ContextTask, *int32) {
+		var attempts int32
+		return func(ctx context.Context) error {
+			if atomic.AddInt32(&attempts, 1) <= failsCount {
+				return err
+			}
+			return nil
+		}, &attempts
+	}
+
+	policy := RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Second * 5,
+		Multiplier:     3,
+	}
+
+	t.Run("retried until success", func(t *testing.T) {
+		clock := &fakeClock{}
+		task, attempts := flaky(3, errTemporary)
+
+		results, err := RunResults(context.Background(), []ContextTask{task},
+			Options{Workers: 1, MaxErrors: 1, Retry: policy, Clock: clock})
+
+		require.NoError(t, err)
+		require.Equal(t, int32(4), *attempts)
+		require.Equal(t, 4, results[0].Attempts)
+
+		// экспоненциальный рост паузы с ограничением сверху
+		require.Equal(t, []time.Duration{time.Second, time.Second * 3, time.Second * 5}, clock.Sleeps())
+		require.Equal(t, time.Second*9, results[0].Duration)
+	})
+
+	t.Run("exhausted retries count as error", func(t *testing.T) {
+		clock := &fakeClock{}
+		failing, attempts := flaky(10, errTemporary)
+		recovering, _ := flaky(1, errTemporary)
+
+		tasks := []ContextTask{recovering, failing, recovering}
+
+		results, err := RunResults(context.Background(), tasks,
+			Options{Workers: 1, MaxErrors: 1, Retry: policy, Clock: clock})
+
+		require.True(t, errors.Is(err, ErrErrorsLimitExceeded))
+		require.Equal(t, int32(4), *attempts)
+		require.Equal(t, 4, results[1].Attempts)
+		require.True(t, results[2].Skipped)
+
+		// повторы не засчитываются в лимит, пока попытки не исчерпаны
+		require.NoError(t, results[0].Err)
+		require.Equal(t, 2, results[0].Attempts)
+	})
+
+	t.Run("not retryable error", func(t *testing.T) {
+		clock := &fakeClock{}
+		task, attempts := flaky(10, errPermanent)
+
+		retryOnlyTemporary := policy
+		retryOnlyTemporary.Retryable = func(err error) bool {
+			return errors.Is(err, errTemporary)
+		}
+
+		results, err := RunResults(context.Background(), []ContextTask{task},
+			Options{Workers: 1, MaxErrors: 1, Retry: retryOnlyTemporary, Clock: clock})
+
+		require.True(t, errors.Is(err, errPermanent))
+		require.Equal(t, int32(1), *attempts)
+		require.Equal(t, 1, results[0].Attempts)
+		require.Empty(t, clock.Sleeps())
+	})
+
+	t.Run("no retries by default", func(t *testing.T) {
+		task, attempts := flaky(1, errTemporary)
+
+		err := RunContext(context.Background(), []ContextTask{task}, Options{Workers: 1, MaxErrors: 1})
+
+		require.Equal(t, ErrErrorsLimitExceeded, err)
+		require.Equal(t, int32(1), *attempts)
+	})
+
+	t.Run("backoff interrupted by cancel", func(t *testing.T) {
+		task, attempts := flaky(10, errTemporary)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer cancel()
+
+		// системные часы: пауза в час прерывается отменой контекста
+		longBackoff := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour}
+
+		start := time.Now()
+		err := RunContext(ctx, []ContextTask{task}, Options{Workers: 1, MaxErrors: 1, Retry: longBackoff})
+
+		require.Equal(t, context.DeadlineExceeded, err)
+		require.Equal(t, int32(1), *attempts)
+		require.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("backoff overflow", func(t *testing.T) {
+		unlimited := RetryPolicy{InitialBackoff: time.Second}
+		require.Equal(t, time.Duration(math.MaxInt64), unlimited.backoff(1000))
+
+		limited := RetryPolicy{InitialBackoff: time.Second, MaxBackoff: time.Minute}
+		require.Equal(t, time.Minute, limited.backoff(1000))
+	})
+
+	t.Run("jitter", func(t *testing.T) {
+		jittered := RetryPolicy{InitialBackoff: time.Second, Jitter: 0.5}
+
+		for i := 0; i < 100; i++ {
+			backoff := jittered.backoff(2)
+			require.GreaterOrEqual(t, int64(backoff), int64(time.Second))
+			require.LessOrEqual(t, int64(backoff), int64(time.Second*3))
+		}
+	})
+}
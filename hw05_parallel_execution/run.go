@@ -1,15 +1,28 @@
 package hw05_parallel_execution //nolint:golint,stylecheck
 
 import (
+	"context"
 	"errors"
 )
 
-var ErrErrorsLimitExceeded = errors.New("errors limit exceeded")
+var (
+	ErrErrorsLimitExceeded = errors.New("errors limit exceeded")
+	ErrNoWorkers           = errors.New("workers count must be positive")
+)
 
 type Task func() error
 
-// Run starts tasks in N goroutines and stops its work when receiving M errors from tasks
+// Run starts tasks in N goroutines and stops its work when receiving M errors from tasks.
+// M <= 0 means errors are ignored and all tasks are run.
 func Run(tasks []Task, N int, M int) error {
-	// Place your code here
-	return nil
+	ctxTasks := make([]ContextTask, 0, len(tasks))
+
+	for _, task := range tasks {
+		task := task
+		ctxTasks = append(ctxTasks, func(context.Context) error {
+			return task()
+		})
+	}
+
+	return RunContext(context.Background(), ctxTasks, Options{Workers: N, MaxErrors: M})
 }
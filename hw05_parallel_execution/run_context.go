@@ -0,0 +1,193 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ContextTask - задача, которую можно прервать через контекст.
+type ContextTask func(ctx context.Context) error
+
+// Options - параметры выполнения задач.
+type Options struct {
+	Workers     int           // количество воркеров (N)
+	MaxErrors   int           // лимит ошибок (M), <= 0 - ошибки игнорируются
+	TaskTimeout time.Duration // ограничение времени выполнения одной попытки задачи, 0 - без ограничения
+	Retry       RetryPolicy   // повтор упавших задач
+	Clock       Clock         // источник времени, nil - системное время
+
+	RateLimit RateLimit             // ограничение частоты запуска задач, в т.ч. повторов
+	Capacity  int64                 // ограничение суммарного веса выполняемых задач, <= 0 - без ограничения
+	Weight    func(index int) int64 // вес задачи, nil - у всех задач вес 1
+
+	// OnProgress получает снимки хода выполнения после запуска и завершения каждой задачи, может быть nil
+	OnProgress func(Progress)
+}
+
+// общие для воркеров параметры выполнения задач.
+type runner struct {
+	opts   Options
+	clock  Clock
+	limits *limits
+}
+
+// RunContext starts tasks in opts.Workers goroutines.
+// When opts.MaxErrors errors are received or ctx is done, no new tasks are started
+// and contexts of running tasks are canceled.
+// Returns ErrErrorsLimitExceeded, ctx.Err() or nil.
+func RunContext(ctx context.Context, tasks []ContextTask, opts Options) error {
+	_, err := execute(ctx, tasks, opts)
+
+	return err
+}
+
+// выполнить задачи, вернуть результаты по каждой и причину остановки.
+func execute(ctx context.Context, tasks []ContextTask, opts Options) ([]TaskResult, error) {
+	if opts.Workers <= 0 {
+		return nil, ErrNoWorkers
+	}
+
+	results := make([]TaskResult, len(tasks))
+	for i := range results {
+		results[i].Index = i
+		results[i].Skipped = true
+	}
+
+	next := 0
+	iterator := func(context.Context) (ContextTask, bool) {
+		if next == len(tasks) {
+			return nil, false
+		}
+		next++
+
+		return tasks[next-1], true
+	}
+
+	// каждый воркер пишет только в элементы results своих задач
+	err := executeIterator(ctx, iterator, len(tasks), opts, func(result TaskResult) int {
+		results[result.Index] = result
+		return 0
+	})
+
+	return results, err
+}
+
+// задача с порядковым номером в источнике.
+type indexedTask struct {
+	index int
+	task  ContextTask
+}
+
+// выполнить total задач из итератора (-1 - количество неизвестно), передавая onResult результаты запущенных задач.
+// onResult вызывается из воркеров одновременно и возвращает количество задач,
+// которые из-за этого результата не будут запущены.
+func executeIterator(ctx context.Context, next TaskIterator, total int, opts Options, onResult func(TaskResult) int) error {
+	if opts.Workers <= 0 {
+		return ErrNoWorkers
+	}
+
+	r := &runner{opts: opts, clock: opts.Clock}
+	if r.clock == nil {
+		r.clock = realClock{}
+	}
+	r.limits = newLimits(opts, r.clock)
+
+	progress := newProgressTracker(total, r.clock, opts.OnProgress)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var errorsCount int32
+
+	// лимит ошибок исчерпан, новые задачи не берём
+	limitExceeded := func() bool {
+		return opts.MaxErrors > 0 && atomic.LoadInt32(&errorsCount) >= int32(opts.MaxErrors)
+	}
+
+	tasksCh := make(chan indexedTask)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(opts.Workers)
+
+	for i := 0; i < opts.Workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for task := range tasksCh {
+				// задача могла быть взята одновременно с отменой
+				if runCtx.Err() != nil {
+					continue
+				}
+
+				result := TaskResult{Index: task.index}
+
+				start := r.clock.Now()
+				result.Attempts, result.Err = r.runWithRetry(runCtx, task.index, task.task, progress.started)
+				result.Duration = r.clock.Now().Sub(start)
+
+				// не дождалась разрешения на запуск до отмены
+				if result.Attempts == 0 && runCtx.Err() != nil {
+					continue
+				}
+
+				// отклонённая лимитами задача считается запущенной и упавшей
+				if result.Attempts == 0 {
+					progress.started()
+				}
+
+				skipped := onResult(result)
+				progress.finished(result.Err, skipped)
+
+				// ошибки после отмены - её следствие, а не сбои задач
+				if result.Err == nil || runCtx.Err() != nil {
+					continue
+				}
+
+				if atomic.AddInt32(&errorsCount, 1) == int32(opts.MaxErrors) {
+					cancel()
+				}
+			}
+		}()
+	}
+
+	// задача уходит только свободному воркеру, поэтому после исчерпания лимита
+	// запустятся не более N уже взятых задач
+feed:
+	for index := 0; !limitExceeded(); index++ {
+		task, ok := next(runCtx)
+		if !ok {
+			break
+		}
+
+		select {
+		case tasksCh <- indexedTask{index: index, task: task}:
+		case <-runCtx.Done():
+			break feed
+		}
+	}
+
+	close(tasksCh)
+	wg.Wait()
+
+	if limitExceeded() {
+		return ErrErrorsLimitExceeded
+	}
+
+	return ctx.Err()
+}
+
+// выполнить одну попытку задачи, паника возвращается как *PanicError.
+func runTask(ctx context.Context, task ContextTask, timeout time.Duration) (err error) {
+	defer recoverPanic(&err)
+
+	if timeout <= 0 {
+		return task(ctx)
+	}
+
+	taskCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return task(taskCtx)
+}
@@ -0,0 +1,93 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestRunContext(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	errTask := errors.New("task error")
+
+	t.Run("errors limit cancels running tasks", func(t *testing.T) {
+		var canceledCount int32
+
+		tasks := []ContextTask{
+			func(ctx context.Context) error {
+				time.Sleep(time.Millisecond * 10)
+				return errTask
+			},
+		}
+		for i := 0; i < 3; i++ {
+			tasks = append(tasks, func(ctx context.Context) error {
+				<-ctx.Done()
+				atomic.AddInt32(&canceledCount, 1)
+				return ctx.Err()
+			})
+		}
+
+		start := time.Now()
+		result := RunContext(context.Background(), tasks, Options{Workers: 4, MaxErrors: 1})
+
+		require.Equal(t, ErrErrorsLimitExceeded, result)
+		require.Equal(t, int32(3), canceledCount)
+		require.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("parent context canceled", func(t *testing.T) {
+		var runTasksCount int32
+
+		tasks := make([]ContextTask, 0, 50)
+		for i := 0; i < 50; i++ {
+			tasks = append(tasks, func(ctx context.Context) error {
+				atomic.AddInt32(&runTasksCount, 1)
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-time.After(time.Second):
+					return nil
+				}
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer cancel()
+
+		start := time.Now()
+		result := RunContext(ctx, tasks, Options{Workers: 5, MaxErrors: 1})
+
+		// ошибки отменённых задач не считаются
+		require.Equal(t, context.DeadlineExceeded, result)
+		require.Equal(t, int32(5), runTasksCount)
+		require.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+
+	t.Run("task timeout", func(t *testing.T) {
+		tasks := []ContextTask{
+			func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			func(ctx context.Context) error {
+				return nil
+			},
+		}
+
+		opts := Options{Workers: 2, MaxErrors: 1, TaskTimeout: time.Millisecond * 20}
+		require.Equal(t, ErrErrorsLimitExceeded, RunContext(context.Background(), tasks, opts))
+
+		opts.MaxErrors = 2
+		require.Nil(t, RunContext(context.Background(), tasks, opts))
+	})
+
+	t.Run("no workers", func(t *testing.T) {
+		require.Equal(t, ErrNoWorkers, RunContext(context.Background(), nil, Options{}))
+	})
+}
@@ -1,6 +1,7 @@
 package hw05_parallel_execution //nolint:golint,stylecheck
 
 import (
+	"errors"
 	"fmt"
 	"math/rand"
 	"sync/atomic"
@@ -66,4 +67,95 @@ func TestRun(t *testing.T) {
 		require.Equal(t, runTasksCount, int32(tasksCount), "not all tasks were completed")
 		require.LessOrEqual(t, int64(elapsedTime), int64(sumTime/2), "tasks were run sequentially?")
 	})
+
+	t.Run("exactly N tasks at once", func(t *testing.T) {
+		tasksCount := 50
+		tasks := make([]Task, 0, tasksCount)
+
+		var runningCount, maxRunningCount int32
+
+		for i := 0; i < tasksCount; i++ {
+			tasks = append(tasks, func() error {
+				running := atomic.AddInt32(&runningCount, 1)
+				defer atomic.AddInt32(&runningCount, -1)
+
+				for {
+					maxRunning := atomic.LoadInt32(&maxRunningCount)
+					if running <= maxRunning || atomic.CompareAndSwapInt32(&maxRunningCount, maxRunning, running) {
+						break
+					}
+				}
+
+				time.Sleep(time.Millisecond * 10)
+				return nil
+			})
+		}
+
+		workersCount := 5
+		result := Run(tasks, workersCount, 1)
+
+		require.Nil(t, result)
+		require.Equal(t, int32(workersCount), maxRunningCount)
+	})
+
+	t.Run("less tasks than workers", func(t *testing.T) {
+		var runTasksCount int32
+
+		tasks := []Task{
+			func() error {
+				atomic.AddInt32(&runTasksCount, 1)
+				return nil
+			},
+			func() error {
+				atomic.AddInt32(&runTasksCount, 1)
+				return nil
+			},
+		}
+
+		require.Nil(t, Run(tasks, 10, 1))
+		require.Equal(t, int32(2), runTasksCount)
+
+		require.Nil(t, Run(nil, 10, 1))
+	})
+
+	// M <= 0 - ошибки игнорируются, выполняются все задачи
+	t.Run("errors ignored if M <= 0", func(t *testing.T) {
+		tasksCount := 50
+		tasks := make([]Task, 0, tasksCount)
+
+		var runTasksCount int32
+
+		for i := 0; i < tasksCount; i++ {
+			err := fmt.Errorf("error from task %d", i)
+			tasks = append(tasks, func() error {
+				atomic.AddInt32(&runTasksCount, 1)
+				return err
+			})
+		}
+
+		for _, maxErrorsCount := range []int{0, -1} {
+			runTasksCount = 0
+
+			result := Run(tasks, 10, maxErrorsCount)
+
+			require.Nil(t, result)
+			require.Equal(t, int32(tasksCount), runTasksCount, "not all tasks were completed")
+		}
+	})
+
+	t.Run("errors limit reached by last tasks", func(t *testing.T) {
+		errTask := errors.New("task error")
+		tasks := []Task{
+			func() error { return nil },
+			func() error { return errTask },
+			func() error { return errTask },
+		}
+
+		require.Equal(t, ErrErrorsLimitExceeded, Run(tasks, 1, 2))
+		require.Nil(t, Run(tasks, 1, 3))
+	})
+
+	t.Run("no workers", func(t *testing.T) {
+		require.Equal(t, ErrNoWorkers, Run([]Task{func() error { return nil }}, 0, 1))
+	})
 }
@@ -0,0 +1,62 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import "context"
+
+// TaskIterator возвращает очередную задачу или false, когда задачи закончились.
+// Ожидая задачу, итератор должен прерываться по отмене ctx.
+type TaskIterator func(ctx context.Context) (ContextTask, bool)
+
+// RunIterator runs tasks taken lazily from next with the same semantics as RunContext.
+// No more tasks are requested after the errors limit is exceeded or ctx is done.
+func RunIterator(ctx context.Context, next TaskIterator, opts Options) error {
+	return executeIterator(ctx, next, -1, opts, func(TaskResult) int { return 0 })
+}
+
+// RunChan runs tasks received from the channel until it is closed, with the same semantics as RunContext.
+// After RunChan returns the channel is no longer read. The errors limit does not cancel ctx,
+// so a producer watching ctx must be stopped by the caller, e.g. by canceling ctx after RunChan returns.
+func RunChan(ctx context.Context, tasks <-chan ContextTask, opts Options) error {
+	return RunIterator(ctx, chanIterator(tasks), opts)
+}
+
+// RunStream starts tasks received from the channel in N goroutines
+// and stops its work when receiving M errors from tasks, like Run.
+// The producer must close the channel: tasks sent after the errors limit is exceeded
+// are read and dropped until then, so the producer never blocks.
+func RunStream(tasks <-chan Task, N int, M int) error {
+	next := func(ctx context.Context) (ContextTask, bool) {
+		select {
+		case task, ok := <-tasks:
+			if !ok {
+				return nil, false
+			}
+
+			return func(context.Context) error {
+				return task()
+			}, true
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+
+	err := RunIterator(context.Background(), next, Options{Workers: N, MaxErrors: M})
+
+	// остаток дочитывается, пока производитель не закроет канал
+	go func() {
+		for range tasks {
+		}
+	}()
+
+	return err
+}
+
+func chanIterator(tasks <-chan ContextTask) TaskIterator {
+	return func(ctx context.Context) (ContextTask, bool) {
+		select {
+		case task, ok := <-tasks:
+			return task, ok
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package hw05_parallel_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestRunStream(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	errTask := errors.New("task error")
+
+	t.Run("lazy producer", func(t *testing.T) {
+		tasksCount := 10_000
+		var producedCount, runTasksCount int32
+
+		next := func(ctx context.Context) (ContextTask, bool) {
+			if atomic.LoadInt32(&producedCount) == int32(tasksCount) {
+				return nil, false
+			}
+			atomic.AddInt32(&producedCount, 1)
+
+			return func(ctx context.Context) error {
+				atomic.AddInt32(&runTasksCount, 1)
+				return nil
+			}, true
+		}
+
+		err := RunIterator(context.Background(), next, Options{Workers: 10, MaxErrors: 1})
+
+		require.NoError(t, err)
+		require.Equal(t, int32(tasksCount), runTasksCount)
+	})
+
+	t.Run("errors limit stops reading", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var producedCount, runTasksCount int32
+
+		// бесконечный производитель, который завершается по отмене
+		tasks := make(chan ContextTask)
+		producerDone := make(chan struct{})
+		go func() {
+			defer close(producerDone)
+			for {
+				task := func(ctx context.Context) error {
+					atomic.AddInt32(&runTasksCount, 1)
+					time.Sleep(time.Millisecond)
+					return errTask
+				}
+
+				select {
+				case tasks <- task:
+					atomic.AddInt32(&producedCount, 1)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		workersCount, maxErrorsCount := 5, 10
+		err := RunChan(ctx, tasks, Options{Workers: workersCount, MaxErrors: maxErrorsCount})
+
+		require.Equal(t, ErrErrorsLimitExceeded, err)
+		require.LessOrEqual(t, runTasksCount, int32(workersCount+maxErrorsCount))
+
+		cancel()
+		<-producerDone
+
+		require.LessOrEqual(t, producedCount, int32(workersCount+maxErrorsCount))
+	})
+
+	t.Run("producer closes channel", func(t *testing.T) {
+		tasks := make(chan Task)
+		var runTasksCount int32
+
+		go func() {
+			defer close(tasks)
+			for i := 0; i < 50; i++ {
+				err := fmt.Errorf("error from task %d", i)
+				tasks <- func() error {
+					if atomic.AddInt32(&runTasksCount, 1)%10 == 0 {
+						return err
+					}
+					return nil
+				}
+			}
+		}()
+
+		require.NoError(t, RunStream(tasks, 5, 0))
+		require.Equal(t, int32(50), runTasksCount)
+	})
+
+	t.Run("slow producer after errors limit", func(t *testing.T) {
+		defer goleak.VerifyNone(t)
+
+		interval := time.Millisecond * 200
+		tasks := make(chan Task)
+		producerDone := make(chan struct{})
+
+		go func() {
+			defer close(producerDone)
+			defer close(tasks)
+			for i := 0; i < 4; i++ {
+				tasks <- func() error {
+					return errTask
+				}
+				time.Sleep(interval)
+			}
+		}()
+
+		// лимит исчерпан первой задачей, следующей отправки RunStream не ждёт
+		start := time.Now()
+		require.Equal(t, ErrErrorsLimitExceeded, RunStream(tasks, 1, 1))
+		require.Less(t, int64(time.Since(start)), int64(interval/2))
+
+		// производитель не блокируется на оставшихся отправках
+		select {
+		case <-producerDone:
+		case <-time.After(interval * 10):
+			require.Fail(t, "producer blocked")
+		}
+	})
+
+	t.Run("context canceled while waiting for tasks", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+		defer cancel()
+
+		// производитель молчит, но канал не закрывает
+		tasks := make(chan ContextTask)
+
+		start := time.Now()
+		err := RunChan(ctx, tasks, Options{Workers: 5, MaxErrors: 1})
+
+		require.Equal(t, context.DeadlineExceeded, err)
+		require.Less(t, int64(time.Since(start)), int64(time.Second))
+	})
+}
@@ -0,0 +1,81 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+// OverflowPolicy - действие при заполненном буфере.
+type OverflowPolicy int
+
+const (
+	Block      OverflowPolicy = iota // ждать места в буфере, задерживая предыдущий этап
+	DropOldest                       // отбросить самое старое значение в буфере
+	DropNewest                       // отбросить поступившее значение
+)
+
+// BufferOptions - параметры буфера между этапами.
+type BufferOptions struct {
+	Size     int            // ёмкость буфера, <= 0 - без буфера
+	Overflow OverflowPolicy // действие при заполненном буфере
+	OnDrop   func()         // вызывается для каждого отброшенного значения, может быть nil
+}
+
+// Buffer возвращает этап-буфер, который ставится между этапами конвеера
+// как отдельный этап или через ExecutePipelineBuffered.
+// С политиками отбрасывания предыдущий этап никогда не ждёт следующего.
+// При закрытии входа оставшиеся в буфере значения отправляются дальше.
+func Buffer[T any](opts BufferOptions) TypedStage[T, T] {
+	return func(in <-chan T) <-chan T {
+		if opts.Size <= 0 || opts.Overflow == Block {
+			size := opts.Size
+			if size < 0 {
+				size = 0
+			}
+
+			out := make(chan T, size)
+			go func() {
+				defer close(out)
+				for v := range in {
+					out <- v
+				}
+			}()
+			return out
+		}
+
+		out := make(chan T)
+		go func() {
+			defer close(out)
+
+			queue := make([]T, 0, opts.Size)
+
+			for in != nil || len(queue) > 0 {
+				// отправка возможна, только когда в очереди есть значения
+				var send chan<- T
+				var head T
+				if len(queue) > 0 {
+					send = out
+					head = queue[0]
+				}
+
+				select {
+				case v, ok := <-in:
+					if !ok {
+						in = nil
+						continue
+					}
+
+					if len(queue) < opts.Size {
+						queue = append(queue, v)
+						continue
+					}
+
+					if opts.Overflow == DropOldest {
+						queue = append(queue[1:], v)
+					}
+					if opts.OnDrop != nil {
+						opts.OnDrop()
+					}
+				case send <- head:
+					queue = queue[1:]
+				}
+			}
+		}()
+		return out
+	}
+}
@@ -0,0 +1,235 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestBuffer(t *testing.T) {
+	data := []int{1, 2, 3, 4, 5, 6}
+
+	// буфер, из которого начинают читать после того, как в него записаны все значения
+	fill := func(opts BufferOptions) []int {
+		in := make(chan int)
+		written := make(chan struct{})
+		go func() {
+			defer close(in)
+			for _, v := range data {
+				in <- v
+			}
+			close(written)
+		}()
+
+		out := Buffer[int](opts)(in)
+
+		select {
+		case <-written:
+		case <-time.After(fault):
+		}
+
+		return collectTyped(out)
+	}
+
+	t.Run("block", func(t *testing.T) {
+		in := make(chan int)
+		out := Buffer[int](BufferOptions{Size: 3})(in)
+
+		// в буфер помещается 3 значения без читателя, плюс одно ждёт у горутины буфера
+		sent := 0
+	send:
+		for _, v := range data {
+			select {
+			case in <- v:
+				sent++
+			case <-time.After(fault / 5):
+				break send
+			}
+		}
+		close(in)
+
+		require.Equal(t, 4, sent)
+		require.Equal(t, data[:sent], collectTyped(out))
+	})
+
+	t.Run("drop oldest", func(t *testing.T) {
+		var dropped int32
+		result := fill(BufferOptions{Size: 3, Overflow: DropOldest, OnDrop: func() { atomic.AddInt32(&dropped, 1) }})
+
+		require.Equal(t, []int{4, 5, 6}, result)
+		require.Equal(t, int32(3), dropped)
+	})
+
+	t.Run("drop newest", func(t *testing.T) {
+		var dropped int32
+		result := fill(BufferOptions{Size: 3, Overflow: DropNewest, OnDrop: func() { atomic.AddInt32(&dropped, 1) }})
+
+		require.Equal(t, []int{1, 2, 3}, result)
+		require.Equal(t, int32(3), dropped)
+	})
+
+	t.Run("unbuffered", func(t *testing.T) {
+		require.Equal(t, data, collectTyped(Buffer[int](BufferOptions{Overflow: DropOldest})(generate(data))))
+	})
+
+	t.Run("in pipeline", func(t *testing.T) {
+		in := make(Bi)
+		go func() {
+			defer close(in)
+			for _, v := range data {
+				in <- v
+			}
+		}()
+
+		buffer := Buffer[interface{}](BufferOptions{Size: len(data), Overflow: DropNewest})
+		stringifier := Map(func(v interface{}) interface{} { return strconv.Itoa(v.(int)) })
+
+		result := make([]string, 0, 10)
+		for s := range ExecutePipeline(in, nil, Stage(buffer), Stage(stringifier)) {
+			result = append(result, s.(string))
+		}
+
+		// в буфер помещаются все значения, отбрасывать нечего
+		require.Equal(t, []string{"1", "2", "3", "4", "5", "6"}, result)
+	})
+
+	t.Run("per-stage buffers", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		release := make(chan struct{})
+		blocked := Stage(Map(func(v interface{}) interface{} {
+			<-release
+			return v
+		}))
+
+		in := make(Bi)
+		buffers := []BufferOptions{{Size: len(data)}}
+		out := ExecutePipelineBuffered(in, nil, buffers, blocked)
+
+		// пока этап ждёт, источник не останавливается: значения копятся в буфере
+		for _, v := range data {
+			select {
+			case in <- v:
+			case <-time.After(fault):
+				require.Fail(t, "source blocked", "value %d", v)
+			}
+		}
+		close(in)
+		close(release)
+
+		result := make([]int, 0, 10)
+		for v := range out {
+			result = append(result, v.(int))
+		}
+		require.Equal(t, data, result)
+	})
+
+	t.Run("per-stage drop", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		release := make(chan struct{})
+		blocked := Stage(Map(func(v interface{}) interface{} {
+			<-release
+			return v
+		}))
+
+		var dropped int32
+		buffers := []BufferOptions{{Size: 2, Overflow: DropNewest, OnDrop: func() { atomic.AddInt32(&dropped, 1) }}}
+
+		in := make(Bi)
+		out := ExecutePipelineBuffered(in, nil, buffers, blocked)
+
+		for _, v := range data {
+			in <- v
+		}
+		close(in)
+		time.Sleep(fault)
+		close(release)
+
+		received := 0
+		for range out {
+			received++
+		}
+		require.Greater(t, atomic.LoadInt32(&dropped), int32(0))
+		require.Equal(t, len(data), received+int(atomic.LoadInt32(&dropped)))
+	})
+
+	t.Run("per-stage buffer stopped", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		release := make(chan struct{})
+		defer close(release)
+		blocked := Stage(Map(func(v interface{}) interface{} {
+			<-release
+			return v
+		}))
+
+		in := make(Bi)
+		done := make(Bi)
+		out := ExecutePipelineBuffered(in, done, []BufferOptions{{Size: len(data)}}, blocked)
+
+		for _, v := range data {
+			in <- v
+		}
+		close(done)
+
+		_, ok := <-out
+		require.False(t, ok)
+		close(in)
+	})
+}
+
+// Бурный источник отдаёт значения пачками, этап обрабатывает их с постоянной скоростью.
+func BenchmarkBuffer(b *testing.B) {
+	const burst = 64
+
+	configs := []struct {
+		name string
+		opts BufferOptions
+	}{
+		{"unbuffered", BufferOptions{}},
+		{"block 16", BufferOptions{Size: 16}},
+		{"block 256", BufferOptions{Size: 256}},
+		{"drop oldest 16", BufferOptions{Size: 16, Overflow: DropOldest}},
+		{"drop newest 16", BufferOptions{Size: 16, Overflow: DropNewest}},
+	}
+
+	work := Map(func(v int) int {
+		// немного работы на значение
+		for i := 0; i < 1000; i++ {
+			v = v*31 + i
+		}
+		return v
+	})
+
+	for _, config := range configs {
+		config := config
+
+		b.Run(config.name, func(b *testing.B) {
+			in := make(chan int)
+			go func() {
+				defer close(in)
+				for i := 0; i < b.N; i++ {
+					in <- i
+					if i%burst == burst-1 {
+						time.Sleep(time.Microsecond * 10)
+					}
+				}
+			}()
+
+			b.ResetTimer()
+
+			out := Then(Then(NewPipeline[int](), Buffer[int](config.opts)), work).Run(in, nil)
+			received := 0
+			for range out {
+				received++
+			}
+
+			b.ReportMetric(float64(b.N-received)/float64(b.N), "dropped/op")
+		})
+	}
+}
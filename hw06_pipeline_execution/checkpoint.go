@@ -0,0 +1,263 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var ErrNotRecord = errors.New("pipeline output is not a Record")
+
+// Record - значение источника с его позицией.
+// В конвеере с контрольными точками этапы получают и отправляют Record, сохраняя Offset.
+type Record struct {
+	Offset int64 // позиция значения в источнике
+	Value  interface{}
+}
+
+// Source - источник, который умеет начинать чтение с позиции.
+type Source interface {
+	// Read отправляет в out значения, начиная с позиции offset, пока они не кончатся или не отменится ctx.
+	// Позиции значений возрастают. Возвращает позицию после последнего прочитанного значения.
+	Read(ctx context.Context, offset int64, out chan<- Record) (int64, error)
+}
+
+// CheckpointOptions - параметры контрольных точек.
+type CheckpointOptions struct {
+	Path     string        // файл контрольной точки
+	Interval time.Duration // как часто сохранять позицию, <= 0 - после каждого значения
+}
+
+// содержимое файла контрольной точки.
+type checkpoint struct {
+	Offset int64 `json:"offset"`
+}
+
+// RecordMap возвращает этап для конвеера с контрольными точками: f получает значение записи,
+// а каждое возвращённое значение уходит дальше с позицией исходной записи.
+// Пустой результат отбрасывает запись.
+func RecordMap(f func(v interface{}) []interface{}) Stage {
+	return Stage(FlatMap(func(v interface{}) []interface{} {
+		record := v.(Record)
+		values := f(record.Value)
+
+		records := make([]interface{}, 0, len(values))
+		for _, value := range values {
+			records = append(records, Record{Offset: record.Offset, Value: value})
+		}
+		return records
+	}))
+}
+
+// RunCheckpointed читает source с сохранённой в opts.Path позиции, пропускает записи через этапы
+// и передаёт значения с выхода в sink, периодически сохраняя позицию обработанных значений.
+// Этапы должны сохранять порядок записей: FanOut и ParallelMap без Ordered не подходят.
+// После сбоя обработка продолжается с сохранённой позиции, поэтому часть записей
+// может попасть в sink повторно (at-least-once).
+// При ошибке sink или отмене ctx сохраняется позиция последнего обработанного значения.
+func RunCheckpointed(
+	ctx context.Context, source Source, opts CheckpointOptions, sink func(v interface{}) error, stages ...Stage,
+) error {
+	offset, err := LoadCheckpoint(opts.Path)
+	if err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	records := make(chan Record)
+	type readResult struct {
+		end int64
+		err error
+	}
+	readDone := make(chan readResult, 1)
+
+	go func() {
+		defer close(records)
+		end, err := source.Read(runCtx, offset, records)
+		readDone <- readResult{end: end, err: err}
+	}()
+
+	in := make(Bi)
+	go func() {
+		defer close(in)
+		for record := range records {
+			select {
+			case in <- record:
+			case <-runCtx.Done():
+				// источник сам завершится по отмене
+				for range records {
+				}
+				return
+			}
+		}
+	}()
+
+	// сохранённая позиция
+	committed := offset
+	lastSave := time.Now()
+
+	save := func(offset int64, force bool) error {
+		if offset == committed && !force {
+			return nil
+		}
+		if !force && opts.Interval > 0 && time.Since(lastSave) < opts.Interval {
+			return nil
+		}
+
+		if err := SaveCheckpoint(opts.Path, offset); err != nil {
+			return err
+		}
+		committed = offset
+		lastSave = time.Now()
+
+		return nil
+	}
+
+	// позиция записи, значения которой сейчас отдаются в sink:
+	// все записи до неё обработаны, а она сама может быть обработана не полностью
+	processed := offset
+	var runErr error
+
+	for v := range ExecutePipelineContext(runCtx, in, stages...) {
+		if runErr != nil {
+			continue
+		}
+
+		record, ok := v.(Record)
+		if !ok {
+			runErr = fmt.Errorf("%w: %T", ErrNotRecord, v)
+			cancel()
+			continue
+		}
+
+		processed = record.Offset
+		if err := save(processed, false); err != nil {
+			runErr = err
+			cancel()
+			continue
+		}
+
+		if err := sink(record.Value); err != nil {
+			runErr = err
+			cancel()
+		}
+	}
+
+	read := <-readDone
+
+	if runErr == nil {
+		runErr = read.err
+	}
+	if runErr == nil {
+		runErr = ctx.Err()
+	}
+	if runErr == nil {
+		// всё прочитанное обработано
+		processed = read.end
+	}
+
+	if err := save(processed, true); err != nil && runErr == nil {
+		runErr = err
+	}
+
+	return runErr
+}
+
+// LoadCheckpoint читает сохранённую позицию, без файла позиция 0.
+func LoadCheckpoint(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return 0, fmt.Errorf("read checkpoint %s: %w", path, err)
+	}
+
+	return cp.Offset, nil
+}
+
+// SaveCheckpoint сохраняет позицию через временный файл, чтобы при сбое не остался испорченный файл.
+func SaveCheckpoint(path string, offset int64) error {
+	data, err := json.Marshal(checkpoint{Offset: offset})
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	return nil
+}
+
+// FileLines - источник строк файла, позиция - смещение начала строки в байтах.
+type FileLines struct {
+	Path string
+}
+
+// Read отправляет строки файла без перевода строки, начиная со смещения offset.
+func (source FileLines) Read(ctx context.Context, offset int64, out chan<- Record) (int64, error) {
+	file, err := os.Open(source.Path)
+	if err != nil {
+		return offset, err
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return offset, err
+	}
+
+	reader := bufio.NewReader(file)
+
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			record := Record{Offset: offset, Value: strings.TrimSuffix(line, "\n")}
+
+			select {
+			case out <- record:
+			case <-ctx.Done():
+				return offset, ctx.Err()
+			}
+
+			offset += int64(len(line))
+		}
+
+		if errors.Is(err, io.EOF) {
+			return offset, nil
+		}
+		if err != nil {
+			return offset, err
+		}
+	}
+}
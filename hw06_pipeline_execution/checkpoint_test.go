@@ -0,0 +1,167 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint(t *testing.T) {
+	errCrash := errors.New("crash")
+
+	// файл со строками line0..line9
+	writeLines := func(t *testing.T, dir string) string {
+		t.Helper()
+
+		lines := make([]string, 10)
+		for i := range lines {
+			lines[i] = "line" + string(rune('0'+i))
+		}
+
+		path := filepath.Join(dir, "input.txt")
+		require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600))
+
+		return path
+	}
+
+	upper := RecordMap(func(v interface{}) []interface{} {
+		return []interface{}{strings.ToUpper(v.(string))}
+	})
+
+	t.Run("resume after crash", func(t *testing.T) {
+		dir := t.TempDir()
+		source := FileLines{Path: writeLines(t, dir)}
+		opts := CheckpointOptions{Path: filepath.Join(dir, "checkpoint.json")}
+
+		var first []string
+		err := RunCheckpointed(context.Background(), source, opts, func(v interface{}) error {
+			if len(first) == 4 {
+				return errCrash
+			}
+			first = append(first, v.(string))
+			return nil
+		}, upper)
+		require.Equal(t, errCrash, err)
+		require.Equal(t, []string{"LINE0", "LINE1", "LINE2", "LINE3"}, first)
+
+		// позиция начала необработанной строки line4
+		offset, err := LoadCheckpoint(opts.Path)
+		require.NoError(t, err)
+		require.Equal(t, int64(len("line0\n")*4), offset)
+
+		var second []string
+		err = RunCheckpointed(context.Background(), source, opts, func(v interface{}) error {
+			second = append(second, v.(string))
+			return nil
+		}, upper)
+		require.NoError(t, err)
+		require.Equal(t, []string{"LINE4", "LINE5", "LINE6", "LINE7", "LINE8", "LINE9"}, second)
+
+		// весь файл обработан
+		offset, err = LoadCheckpoint(opts.Path)
+		require.NoError(t, err)
+		require.Equal(t, int64(len("line0\n")*10-1), offset)
+
+		var third []string
+		require.NoError(t, RunCheckpointed(context.Background(), source, opts, func(v interface{}) error {
+			third = append(third, v.(string))
+			return nil
+		}, upper))
+		require.Empty(t, third)
+	})
+
+	t.Run("partially processed record is repeated", func(t *testing.T) {
+		dir := t.TempDir()
+		source := FileLines{Path: writeLines(t, dir)}
+		opts := CheckpointOptions{Path: filepath.Join(dir, "checkpoint.json")}
+
+		// каждая строка даёт два значения, нечётные строки отбрасываются
+		split := RecordMap(func(v interface{}) []interface{} {
+			s := v.(string)
+			if (s[len(s)-1]-'0')%2 != 0 {
+				return nil
+			}
+			return []interface{}{s + "a", s + "b"}
+		})
+
+		var first []string
+		err := RunCheckpointed(context.Background(), source, opts, func(v interface{}) error {
+			if v.(string) == "line2b" {
+				return errCrash
+			}
+			first = append(first, v.(string))
+			return nil
+		}, split)
+		require.Equal(t, errCrash, err)
+		require.Equal(t, []string{"line0a", "line0b", "line2a"}, first)
+
+		var second []string
+		err = RunCheckpointed(context.Background(), source, opts, func(v interface{}) error {
+			second = append(second, v.(string))
+			return nil
+		}, split)
+		require.NoError(t, err)
+
+		// line2a обрабатывается повторно
+		require.Equal(t, []string{"line2a", "line2b", "line4a", "line4b", "line6a", "line6b", "line8a", "line8b"}, second)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		dir := t.TempDir()
+		source := FileLines{Path: writeLines(t, dir)}
+		opts := CheckpointOptions{Path: filepath.Join(dir, "checkpoint.json"), Interval: time.Hour}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		count := 0
+		err := RunCheckpointed(ctx, source, opts, func(v interface{}) error {
+			count++
+			if count == 3 {
+				cancel()
+			}
+			return nil
+		})
+		require.True(t, errors.Is(err, context.Canceled))
+
+		// при остановке позиция сохраняется, несмотря на интервал
+		offset, err := LoadCheckpoint(opts.Path)
+		require.NoError(t, err)
+		require.Greater(t, offset, int64(0))
+		require.LessOrEqual(t, offset, int64(len("line0\n")*3))
+	})
+
+	t.Run("not a record", func(t *testing.T) {
+		dir := t.TempDir()
+		source := FileLines{Path: writeLines(t, dir)}
+		opts := CheckpointOptions{Path: filepath.Join(dir, "checkpoint.json")}
+
+		unwrap := Stage(Map(func(v interface{}) interface{} { return v.(Record).Value }))
+
+		err := RunCheckpointed(context.Background(), source, opts, func(v interface{}) error { return nil }, unwrap)
+		require.True(t, errors.Is(err, ErrNotRecord))
+	})
+
+	t.Run("checkpoint file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+		offset, err := LoadCheckpoint(path)
+		require.NoError(t, err)
+		require.Zero(t, offset)
+
+		require.NoError(t, SaveCheckpoint(path, 42))
+		offset, err = LoadCheckpoint(path)
+		require.NoError(t, err)
+		require.Equal(t, int64(42), offset)
+
+		require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+		_, err = LoadCheckpoint(path)
+		require.Error(t, err)
+	})
+}
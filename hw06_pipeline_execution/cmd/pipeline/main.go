@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	pipeline "github.com/elak/golang_home_work/hw06_pipeline_execution"
+)
+
+var (
+	configPath string
+	listStages bool
+)
+
+func init() {
+	flag.StringVar(&configPath, "config", "", "pipeline description, .json or .yaml")
+	flag.BoolVar(&listStages, "list", false, "print registered stages and exit")
+}
+
+func main() {
+	flag.Parse()
+
+	registry := pipeline.NewRegistry()
+
+	if listStages {
+		fmt.Println(strings.Join(registry.Names(), "\n"))
+		return
+	}
+
+	if configPath == "" {
+		log.Fatal("config is required")
+	}
+
+	config, err := pipeline.LoadConfig(configPath)
+	if err != nil {
+		log.Fatalf("failed to load config: %s", err)
+	}
+
+	stages, err := registry.Build(config)
+	if err != nil {
+		log.Fatalf("failed to build pipeline: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+		<-signals
+		signal.Stop(signals)
+		cancel()
+	}()
+
+	err = run(ctx, stages)
+	cancel()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// пропустить строки stdin через конвеер, выводя результаты в stdout.
+func run(ctx context.Context, stages []pipeline.Stage) error {
+	in := make(pipeline.Bi)
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(in)
+
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			select {
+			case in <- scanner.Text():
+			case <-ctx.Done():
+				readErr <- nil
+				return
+			}
+		}
+		readErr <- scanner.Err()
+	}()
+
+	writer := bufio.NewWriter(os.Stdout)
+
+	for v := range pipeline.ExecutePipelineContext(ctx, in, stages...) {
+		if _, err := fmt.Fprintln(writer, v); err != nil {
+			return fmt.Errorf("failed to write output: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write output: %w", err)
+	}
+
+	// после отмены чтение stdin может так и не завершиться
+	select {
+	case err := <-readErr:
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+	case <-ctx.Done():
+	}
+
+	return nil
+}
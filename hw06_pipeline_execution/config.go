@@ -0,0 +1,348 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	ErrUnknownStage    = errors.New("unknown stage")
+	ErrStageExists     = errors.New("stage already registered")
+	ErrInvalidParam    = errors.New("invalid stage parameter")
+	ErrUnknownFormat   = errors.New("unknown config format")
+	ErrUnknownOverflow = errors.New("unknown overflow policy")
+	ErrEmptyStageName  = errors.New("stage name is empty")
+	ErrStatefulStage   = errors.New("stateful stage cannot run in parallel")
+)
+
+// PipelineConfig - описание конвеера.
+type PipelineConfig struct {
+	Stages []StageConfig `json:"stages" yaml:"stages"`
+}
+
+// StageConfig - описание этапа конвеера.
+type StageConfig struct {
+	Name     string `json:"name" yaml:"name"`         // имя этапа в реестре
+	Params   Params `json:"params" yaml:"params"`     // параметры фабрики этапа
+	Parallel int    `json:"parallel" yaml:"parallel"` // количество копий этапа (FanOut), порядок значений при этом не сохраняется, для этапов с состоянием - не больше 1
+	Buffer   int    `json:"buffer" yaml:"buffer"`     // размер буфера перед этапом
+	Overflow string `json:"overflow" yaml:"overflow"` // block (по умолчанию), drop_oldest или drop_newest
+}
+
+// Params - параметры этапа из описания.
+type Params map[string]interface{}
+
+// String возвращает строковый параметр или def, если параметра нет.
+func (params Params) String(key, def string) (string, error) {
+	value, ok := params[key]
+	if !ok {
+		return def, nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("%w %q: expected string, got %v", ErrInvalidParam, key, value)
+	}
+
+	return s, nil
+}
+
+// Int возвращает целый параметр или def, если параметра нет.
+func (params Params) Int(key string, def int) (int, error) {
+	value, ok := params[key]
+	if !ok {
+		return def, nil
+	}
+
+	// JSON разбирает числа как float64, YAML - как int
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w %q: expected integer, got %v", ErrInvalidParam, key, value)
+}
+
+// Float возвращает числовой параметр или def, если параметра нет.
+func (params Params) Float(key string, def float64) (float64, error) {
+	value, ok := params[key]
+	if !ok {
+		return def, nil
+	}
+
+	switch v := value.(type) {
+	case int:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+
+	return 0, fmt.Errorf("%w %q: expected number, got %v", ErrInvalidParam, key, value)
+}
+
+// Duration возвращает параметр-длительность в формате time.ParseDuration или def, если параметра нет.
+func (params Params) Duration(key string, def time.Duration) (time.Duration, error) {
+	s, err := params.String(key, "")
+	if err != nil || s == "" {
+		return def, err
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w %q: %s", ErrInvalidParam, key, err)
+	}
+
+	return d, nil
+}
+
+// StageFactory создаёт этап по параметрам из описания.
+type StageFactory func(params Params) (Stage, error)
+
+// Registry - реестр фабрик этапов по именам.
+type Registry struct {
+	mutex     sync.RWMutex
+	factories map[string]StageFactory
+	stateful  map[string]bool // этапы, которые нельзя запускать в нескольких копиях
+}
+
+// NewRegistry возвращает реестр со встроенными этапами для строк:
+// upper, lower, trim, prefix (value), suffix (value), replace (old, new),
+// grep (pattern), exclude (pattern), dedupe и throttle (rate).
+// dedupe и throttle хранят состояние и не запускаются в нескольких копиях.
+func NewRegistry() *Registry {
+	registry := &Registry{
+		factories: make(map[string]StageFactory),
+		stateful:  map[string]bool{"dedupe": true, "throttle": true},
+	}
+
+	for name, factory := range builtinStages() {
+		registry.factories[name] = factory
+	}
+
+	return registry
+}
+
+// Register добавляет фабрику этапа под именем name.
+func (registry *Registry) Register(name string, factory StageFactory) error {
+	return registry.register(name, factory, false)
+}
+
+// RegisterStateful добавляет фабрику этапа, хранящего состояние между значениями.
+// Копии такого этапа не делили бы состояние, поэтому parallel > 1 для него - ошибка.
+func (registry *Registry) RegisterStateful(name string, factory StageFactory) error {
+	return registry.register(name, factory, true)
+}
+
+func (registry *Registry) register(name string, factory StageFactory, stateful bool) error {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if _, exists := registry.factories[name]; exists {
+		return fmt.Errorf("%w: %q", ErrStageExists, name)
+	}
+	registry.factories[name] = factory
+	registry.stateful[name] = stateful
+
+	return nil
+}
+
+// Names возвращает имена зарегистрированных этапов по алфавиту.
+func (registry *Registry) Names() []string {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	names := make([]string, 0, len(registry.factories))
+	for name := range registry.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Build создаёт этапы для ExecutePipeline по описанию.
+func (registry *Registry) Build(config PipelineConfig) ([]Stage, error) {
+	stages := make([]Stage, 0, len(config.Stages))
+
+	for i, stageConfig := range config.Stages {
+		built, err := registry.buildStage(stageConfig)
+		if err != nil {
+			return nil, fmt.Errorf("stage %d: %w", i, err)
+		}
+		stages = append(stages, built...)
+	}
+
+	return stages, nil
+}
+
+// создать этап с буфером перед ним.
+func (registry *Registry) buildStage(config StageConfig) ([]Stage, error) {
+	if config.Name == "" {
+		return nil, ErrEmptyStageName
+	}
+
+	registry.mutex.RLock()
+	factory, ok := registry.factories[config.Name]
+	stateful := registry.stateful[config.Name]
+	registry.mutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStage, config.Name)
+	}
+
+	if stateful && config.Parallel > 1 {
+		return nil, fmt.Errorf("%w: %q", ErrStatefulStage, config.Name)
+	}
+
+	var overflow OverflowPolicy
+	switch config.Overflow {
+	case "", "block":
+		overflow = Block
+	case "drop_oldest":
+		overflow = DropOldest
+	case "drop_newest":
+		overflow = DropNewest
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownOverflow, config.Overflow)
+	}
+
+	stage, err := factory(config.Params)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", config.Name, err)
+	}
+
+	stages := make([]Stage, 0, 2)
+	if config.Buffer > 0 {
+		stages = append(stages, Stage(Buffer[interface{}](BufferOptions{Size: config.Buffer, Overflow: overflow})))
+	}
+
+	return append(stages, FanOut(config.Parallel, stage)), nil
+}
+
+// ParseConfig разбирает описание конвеера в формате "json" или "yaml".
+func ParseConfig(data []byte, format string) (PipelineConfig, error) {
+	var config PipelineConfig
+	var err error
+
+	switch format {
+	case "json":
+		// неизвестные поля - ошибка, как и в YAML
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&config)
+	case "yaml", "yml":
+		err = yaml.UnmarshalStrict(data, &config)
+	default:
+		return PipelineConfig{}, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+
+	if err != nil {
+		return PipelineConfig{}, fmt.Errorf("parse %s config: %w", format, err)
+	}
+
+	return config, nil
+}
+
+// LoadConfig читает описание конвеера из файла, формат определяется по расширению.
+func LoadConfig(path string) (PipelineConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PipelineConfig{}, err
+	}
+
+	return ParseConfig(data, strings.TrimPrefix(filepath.Ext(path), "."))
+}
+
+// встроенные этапы для строк.
+func builtinStages() map[string]StageFactory {
+	// этап, применяющий f к строкам
+	mapString := func(f func(s string) string) StageFactory {
+		return func(Params) (Stage, error) {
+			return Stage(Map(func(v interface{}) interface{} { return f(v.(string)) })), nil
+		}
+	}
+
+	// этап, пропускающий строки, совпадение которых с pattern равно match
+	grep := func(match bool) StageFactory {
+		return func(params Params) (Stage, error) {
+			pattern, err := params.String("pattern", "")
+			if err != nil {
+				return nil, err
+			}
+
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("%w \"pattern\": %s", ErrInvalidParam, err)
+			}
+
+			return Stage(Filter(func(v interface{}) bool { return re.MatchString(v.(string)) == match })), nil
+		}
+	}
+
+	// этап, дополняющий строки значением параметра value
+	affix := func(f func(s, value string) string) StageFactory {
+		return func(params Params) (Stage, error) {
+			value, err := params.String("value", "")
+			if err != nil {
+				return nil, err
+			}
+
+			return Stage(Map(func(v interface{}) interface{} { return f(v.(string), value) })), nil
+		}
+	}
+
+	return map[string]StageFactory{
+		"upper": mapString(strings.ToUpper),
+		"lower": mapString(strings.ToLower),
+		"trim":  mapString(strings.TrimSpace),
+		"prefix": affix(func(s, value string) string {
+			return value + s
+		}),
+		"suffix": affix(func(s, value string) string {
+			return s + value
+		}),
+		"replace": func(params Params) (Stage, error) {
+			oldValue, err := params.String("old", "")
+			if err != nil {
+				return nil, err
+			}
+			newValue, err := params.String("new", "")
+			if err != nil {
+				return nil, err
+			}
+
+			return Stage(Map(func(v interface{}) interface{} {
+				return strings.ReplaceAll(v.(string), oldValue, newValue)
+			})), nil
+		},
+		"grep":    grep(true),
+		"exclude": grep(false),
+		"dedupe": func(Params) (Stage, error) {
+			return Stage(Dedupe(func(v interface{}) string { return v.(string) })), nil
+		},
+		"throttle": func(params Params) (Stage, error) {
+			rate, err := params.Float("rate", 0)
+			if err != nil {
+				return nil, err
+			}
+
+			// сигнал остановки этапу не передаётся, ожидание длится не дольше интервала
+			return Stage(Throttle[interface{}](nil, rate)), nil
+		},
+	}
+}
@@ -0,0 +1,207 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	lines := func(data ...string) In {
+		in := make(Bi)
+		go func() {
+			defer close(in)
+			for _, v := range data {
+				in <- v
+			}
+		}()
+		return in
+	}
+
+	run := func(t *testing.T, registry *Registry, config PipelineConfig, data ...string) []string {
+		t.Helper()
+
+		stages, err := registry.Build(config)
+		require.NoError(t, err)
+
+		result := make([]string, 0, len(data))
+		for v := range ExecutePipeline(lines(data...), nil, stages...) {
+			result = append(result, v.(string))
+		}
+		return result
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		config, err := ParseConfig([]byte(`
+stages:
+  - name: trim
+  - name: exclude
+    params:
+      pattern: "^#"
+  - name: replace
+    params: {old: "o", new: "0"}
+  - name: upper
+    parallel: 3
+    buffer: 2
+  - name: dedupe
+  - name: suffix
+    params:
+      value: "!"
+`), "yaml")
+		require.NoError(t, err)
+		require.Len(t, config.Stages, 6)
+		require.Equal(t, 3, config.Stages[3].Parallel)
+
+		result := run(t, NewRegistry(), config, " foo", "# comment", "bar ", "foo", "baz")
+		sort.Strings(result)
+		require.Equal(t, []string{"BAR!", "BAZ!", "F00!"}, result)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		config, err := ParseConfig([]byte(`{"stages": [
+			{"name": "grep", "params": {"pattern": "^a"}},
+			{"name": "prefix", "params": {"value": "- "}},
+			{"name": "throttle", "params": {"rate": 1000}, "buffer": 10, "overflow": "drop_newest"}
+		]}`), "json")
+		require.NoError(t, err)
+
+		result := run(t, NewRegistry(), config, "ab", "ba", "ac")
+		require.Equal(t, []string{"- ab", "- ac"}, result)
+	})
+
+	t.Run("custom stage", func(t *testing.T) {
+		registry := NewRegistry()
+		err := registry.Register("repeat", func(params Params) (Stage, error) {
+			times, err := params.Int("times", 1)
+			if err != nil {
+				return nil, err
+			}
+
+			return Stage(Map(func(v interface{}) interface{} {
+				return strings.Repeat(v.(string), times)
+			})), nil
+		})
+		require.NoError(t, err)
+
+		err = registry.Register("repeat", nil)
+		require.True(t, errors.Is(err, ErrStageExists))
+		require.Contains(t, registry.Names(), "repeat")
+
+		config, err := ParseConfig([]byte(`{"stages": [{"name": "repeat", "params": {"times": 3}}]}`), "json")
+		require.NoError(t, err)
+		require.Equal(t, []string{"aaa", "bbb"}, run(t, registry, config, "a", "b"))
+
+		config, err = ParseConfig([]byte("stages:\n  - name: repeat\n    params: {times: 2}\n"), "yaml")
+		require.NoError(t, err)
+		require.Equal(t, []string{"aa"}, run(t, registry, config, "a"))
+	})
+
+	t.Run("stateful stages", func(t *testing.T) {
+		registry := NewRegistry()
+
+		for _, name := range []string{"dedupe", "throttle"} {
+			_, err := registry.Build(PipelineConfig{Stages: []StageConfig{{Name: name, Parallel: 2}}})
+			require.True(t, errors.Is(err, ErrStatefulStage))
+			require.EqualError(t, err, fmt.Sprintf("stage 0: stateful stage cannot run in parallel: %q", name))
+
+			_, err = registry.Build(PipelineConfig{Stages: []StageConfig{{Name: name, Parallel: 1}}})
+			require.NoError(t, err)
+		}
+
+		// одна копия dedupe видит все значения
+		config := PipelineConfig{Stages: []StageConfig{{Name: "dedupe"}}}
+		require.Equal(t, []string{"a", "b"}, run(t, registry, config, "a", "b", "a", "b", "a"))
+
+		err := registry.RegisterStateful("count", func(Params) (Stage, error) {
+			count := 0
+			return Stage(Map(func(v interface{}) interface{} {
+				count++
+				return fmt.Sprint(v, count)
+			})), nil
+		})
+		require.NoError(t, err)
+
+		_, err = registry.Build(PipelineConfig{Stages: []StageConfig{{Name: "count", Parallel: 3}}})
+		require.True(t, errors.Is(err, ErrStatefulStage))
+
+		config = PipelineConfig{Stages: []StageConfig{{Name: "count"}}}
+		require.Equal(t, []string{"a1", "b2"}, run(t, registry, config, "a", "b"))
+	})
+
+	t.Run("load file", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "pipeline.yml")
+		require.NoError(t, os.WriteFile(path, []byte("stages:\n  - name: lower\n"), 0o600))
+
+		config, err := LoadConfig(path)
+		require.NoError(t, err)
+		require.Equal(t, []string{"abc"}, run(t, NewRegistry(), config, "ABC"))
+
+		_, err = LoadConfig(filepath.Join(dir, "pipeline.toml"))
+		require.Error(t, err)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		registry := NewRegistry()
+
+		_, err := ParseConfig([]byte("stages: []"), "toml")
+		require.True(t, errors.Is(err, ErrUnknownFormat))
+
+		_, err = ParseConfig([]byte("stages:\n  - name: upper\n    workers: 2\n"), "yaml")
+		require.Error(t, err)
+
+		_, err = ParseConfig([]byte(`{"stages": [{"name": "upper", "workers": 2}]}`), "json")
+		require.Error(t, err)
+
+		_, err = registry.Build(PipelineConfig{Stages: []StageConfig{{Name: "nope"}}})
+		require.True(t, errors.Is(err, ErrUnknownStage))
+		require.EqualError(t, err, `stage 0: unknown stage: "nope"`)
+
+		_, err = registry.Build(PipelineConfig{Stages: []StageConfig{{}}})
+		require.True(t, errors.Is(err, ErrEmptyStageName))
+
+		_, err = registry.Build(PipelineConfig{Stages: []StageConfig{{Name: "upper", Overflow: "spill"}}})
+		require.True(t, errors.Is(err, ErrUnknownOverflow))
+
+		_, err = registry.Build(PipelineConfig{Stages: []StageConfig{
+			{Name: "grep", Params: Params{"pattern": "("}},
+		}})
+		require.True(t, errors.Is(err, ErrInvalidParam))
+
+		_, err = registry.Build(PipelineConfig{Stages: []StageConfig{
+			{Name: "throttle", Params: Params{"rate": "fast"}},
+		}})
+		require.True(t, errors.Is(err, ErrInvalidParam))
+	})
+
+	t.Run("params", func(t *testing.T) {
+		params := Params{"n": 2, "f": 2.0, "half": 2.5, "s": "x", "d": "1s"}
+
+		n, err := params.Int("f", 0)
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+
+		_, err = params.Int("half", 0)
+		require.True(t, errors.Is(err, ErrInvalidParam))
+
+		n, err = params.Int("missing", 7)
+		require.NoError(t, err)
+		require.Equal(t, 7, n)
+
+		d, err := params.Duration("d", 0)
+		require.NoError(t, err)
+		require.Equal(t, int64(1e9), int64(d))
+
+		_, err = params.Duration("s", 0)
+		require.True(t, errors.Is(err, ErrInvalidParam))
+
+		_, err = params.String("n", "")
+		require.True(t, errors.Is(err, ErrInvalidParam))
+	})
+}
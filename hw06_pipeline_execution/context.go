@@ -0,0 +1,16 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import "context"
+
+// ExecutePipelineContext запускает конвеер как ExecutePipeline, останавливая его по отмене ctx.
+// Отмена учитывается и при чтении, и при отправке значений между этапами,
+// а выходы этапов дочитываются, поэтому этапы, завершающиеся по закрытию входа, не остаются висеть.
+// Источник in конвеер не дочитывает: отправитель сам должен следить за ctx.
+func ExecutePipelineContext(ctx context.Context, in In, stages ...Stage) Out {
+	return executeStages(in, stopSignal{ctx: ctx.Done()}, nil, stages)
+}
+
+// RunContext запускает типизированный конвеер, останавливая его по отмене ctx, как ExecutePipelineContext.
+func (pipeline Pipeline[I, O]) RunContext(ctx context.Context, in <-chan I) <-chan O {
+	return pipeline.execute(in, stopSignal{ctx: ctx.Done()})
+}
@@ -0,0 +1,142 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestPipelineContext(t *testing.T) {
+	g := func(f func(v interface{}) interface{}) Stage {
+		return Untyped(typedStage(f))
+	}
+
+	stages := []Stage{
+		g(func(v interface{}) interface{} { return v }),
+		g(func(v interface{}) interface{} { return v.(int) * 2 }),
+		g(func(v interface{}) interface{} { return v.(int) + 100 }),
+		g(func(v interface{}) interface{} { return strconv.Itoa(v.(int)) }),
+	}
+
+	// бесконечный источник, следящий за ctx
+	source := func(ctx context.Context) In {
+		in := make(Bi)
+		go func() {
+			defer close(in)
+			for i := 1; ; i++ {
+				select {
+				case in <- i:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		return in
+	}
+
+	t.Run("simple case", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		in := make(Bi)
+		go func() {
+			defer close(in)
+			for _, v := range []int{1, 2, 3, 4, 5} {
+				in <- v
+			}
+		}()
+
+		result := make([]string, 0, 10)
+		for s := range ExecutePipelineContext(context.Background(), in, stages...) {
+			result = append(result, s.(string))
+		}
+
+		require.Equal(t, []string{"102", "104", "106", "108", "110"}, result)
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		ctx, cancel := context.WithTimeout(context.Background(), sleepPerStage*2)
+		defer cancel()
+
+		result := make([]string, 0, 10)
+		start := time.Now()
+		for s := range ExecutePipelineContext(ctx, source(ctx), stages...) {
+			result = append(result, s.(string))
+		}
+		elapsed := time.Since(start)
+
+		require.Len(t, result, 0)
+		require.Less(t, int64(elapsed), int64(sleepPerStage*2)+int64(fault))
+	})
+
+	t.Run("reader stops reading", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		fast := Stage(func(in In) Out {
+			out := make(Bi)
+			go func() {
+				defer close(out)
+				for v := range in {
+					out <- v
+				}
+			}()
+			return out
+		})
+
+		out := ExecutePipelineContext(ctx, source(ctx), fast, fast, fast)
+		require.Equal(t, 1, <-out)
+		require.Equal(t, 2, <-out)
+
+		// значения больше не читаются, но все этапы завершаются
+		cancel()
+	})
+
+	t.Run("typed", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		in := make(chan int)
+		go func() {
+			defer close(in)
+			for i := 0; ; i++ {
+				select {
+				case in <- i:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		pipeline := Then(NewPipeline[int]().Pipe(typedStage(func(v int) int { return v * 2 })), typedStage(strconv.Itoa))
+		out := pipeline.RunContext(ctx, in)
+
+		require.Equal(t, "0", <-out)
+		cancel()
+
+		for range out {
+		}
+	})
+
+	t.Run("parallel", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		parallel := ParallelMap(ParallelOptions{Workers: 4, Ordered: true}, func(v interface{}) interface{} {
+			time.Sleep(time.Millisecond)
+			return v
+		})
+
+		out := ExecutePipelineContext(ctx, source(ctx), Stage(parallel), FanOut(3, stages[0]))
+		<-out
+		cancel()
+	})
+}
@@ -0,0 +1,136 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ErrorPolicy - действие этапа при ошибке обработки значения.
+type ErrorPolicy int
+
+const (
+	SkipOnError       ErrorPolicy = iota // значение отбрасывается, обработка продолжается
+	StopOnError                          // конвеер останавливается, ошибка уходит в канал ошибок
+	DeadLetterOnError                    // значение с ошибкой уходит в DeadLetter, обработка продолжается
+)
+
+// StageError - ошибка обработки значения этапом.
+type StageError struct {
+	Stage string      // имя этапа
+	Value interface{} // входное значение этапа
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("stage %q: %s", e.Stage, e.Err)
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// FallibleStage - этап, обработка значения в котором может завершиться ошибкой.
+type FallibleStage struct {
+	Name       string
+	Run        func(v interface{}) (interface{}, error)
+	Policy     ErrorPolicy
+	DeadLetter chan<- *StageError // получатель значений с ошибками при DeadLetterOnError, nil - значения отбрасываются
+}
+
+// ExecutePipelineWithErrors запускает конвеер из этапов с ошибками.
+// Канал ошибок получает не больше одной ошибки - *StageError, остановившую конвеер по StopOnError,
+// и закрывается после завершения всех этапов, поэтому его можно читать после выхода.
+// Получатели DeadLetter должны читать их одновременно с выходом конвеера.
+func ExecutePipelineWithErrors(in In, done In, stages ...FallibleStage) (Out, <-chan error) {
+	errs := make(chan error, 1)
+
+	// остановка по done или по ошибке этапа
+	stopped := make(Bi)
+	finished := make(chan struct{})
+	var once sync.Once
+	stop := func() {
+		once.Do(func() { close(stopped) })
+	}
+
+	go func() {
+		select {
+		case <-done:
+			stop()
+		case <-finished:
+		}
+	}()
+
+	wg := sync.WaitGroup{}
+	nextStageInput := in
+
+	for _, stage := range stages {
+		if stage.Run == nil {
+			continue
+		}
+
+		wg.Add(1)
+		nextStageInput = runFallible(interrupt(nextStageInput, stopSignal{done: stopped}, false), stopped, stage, func(err *StageError) {
+			once.Do(func() {
+				errs <- err
+				close(stopped)
+			})
+		}, wg.Done)
+	}
+
+	go func() {
+		wg.Wait()
+		close(finished)
+		close(errs)
+	}()
+
+	return nextStageInput, errs
+}
+
+// запустить этап, fail вызывается при ошибке с StopOnError, exit - по завершении этапа.
+func runFallible(in In, stopped In, stage FallibleStage, fail func(err *StageError), exit func()) Out {
+	out := make(Bi)
+
+	go func() {
+		defer exit()
+		defer close(out)
+
+		// после остановки дочитываем вход, чтобы не заблокировать предыдущий этап
+		defer func() {
+			for range in {
+			}
+		}()
+
+		for v := range in {
+			result, err := stage.Run(v)
+
+			if err == nil {
+				select {
+				case out <- result:
+				case <-stopped:
+					return
+				}
+				continue
+			}
+
+			stageErr := &StageError{Stage: stage.Name, Value: v, Err: err}
+
+			switch stage.Policy {
+			case SkipOnError:
+			case StopOnError:
+				fail(stageErr)
+				return
+			case DeadLetterOnError:
+				if stage.DeadLetter == nil {
+					continue
+				}
+				select {
+				case stage.DeadLetter <- stageErr:
+				case <-stopped:
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,130 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func TestPipelineWithErrors(t *testing.T) {
+	errOdd := errors.New("odd value")
+
+	// этап, не принимающий нечётные значения
+	evenOnly := func(policy ErrorPolicy, deadLetter chan<- *StageError) FallibleStage {
+		return FallibleStage{
+			Name: "even only",
+			Run: func(v interface{}) (interface{}, error) {
+				if v.(int)%2 != 0 {
+					return nil, errOdd
+				}
+				return v, nil
+			},
+			Policy:     policy,
+			DeadLetter: deadLetter,
+		}
+	}
+
+	stringifier := FallibleStage{
+		Name: "stringifier",
+		Run: func(v interface{}) (interface{}, error) {
+			return strconv.Itoa(v.(int)), nil
+		},
+	}
+
+	source := func(data []int, done In) In {
+		in := make(Bi)
+		go func() {
+			defer close(in)
+			for _, v := range data {
+				select {
+				case in <- v:
+				case <-done:
+					return
+				}
+			}
+		}()
+		return in
+	}
+
+	collect := func(out Out) []string {
+		result := make([]string, 0, 10)
+		for s := range out {
+			result = append(result, s.(string))
+		}
+		return result
+	}
+
+	t.Run("skip", func(t *testing.T) {
+		out, errs := ExecutePipelineWithErrors(source([]int{1, 2, 3, 4}, nil), nil, evenOnly(SkipOnError, nil), stringifier)
+
+		require.Equal(t, []string{"2", "4"}, collect(out))
+		require.NoError(t, <-errs)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		out, errs := ExecutePipelineWithErrors(source([]int{2, 4, 5, 6, 8}, nil), nil, evenOnly(StopOnError, nil), stringifier)
+
+		result := collect(out)
+		require.LessOrEqual(t, len(result), 2)
+
+		err := <-errs
+		var stageErr *StageError
+		require.True(t, errors.As(err, &stageErr))
+		require.Equal(t, 5, stageErr.Value)
+		require.True(t, errors.Is(err, errOdd))
+		require.EqualError(t, err, `stage "even only": odd value`)
+
+		_, ok := <-errs
+		require.False(t, ok)
+	})
+
+	t.Run("dead letter", func(t *testing.T) {
+		deadLetter := make(chan *StageError)
+		dead := make(chan []interface{})
+		go func() {
+			var values []interface{}
+			for stageErr := range deadLetter {
+				values = append(values, stageErr.Value)
+			}
+			dead <- values
+		}()
+
+		out, errs := ExecutePipelineWithErrors(source([]int{1, 2, 3, 4, 5}, nil), nil,
+			evenOnly(DeadLetterOnError, deadLetter), stringifier)
+
+		require.Equal(t, []string{"2", "4"}, collect(out))
+		require.NoError(t, <-errs)
+
+		close(deadLetter)
+		require.Equal(t, []interface{}{1, 3, 5}, <-dead)
+	})
+
+	t.Run("done case", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		done := make(Bi)
+		go func() {
+			<-time.After(fault)
+			close(done)
+		}()
+
+		slow := FallibleStage{
+			Name: "slow",
+			Run: func(v interface{}) (interface{}, error) {
+				time.Sleep(sleepPerStage)
+				return v, nil
+			},
+		}
+
+		start := time.Now()
+		out, errs := ExecutePipelineWithErrors(source([]int{1, 2, 3, 4}, done), done, slow, slow, stringifier)
+
+		require.Empty(t, collect(out))
+		require.NoError(t, <-errs)
+		require.Less(t, int64(time.Since(start)), int64(sleepPerStage)+int64(fault))
+	})
+}
@@ -1,5 +1,15 @@
 module github.com/elak/golang_home_work/hw06_pipeline_execution
 
-go 1.15
+go 1.18
 
-require github.com/stretchr/testify v1.5.1
+require (
+	github.com/stretchr/testify v1.7.0
+	go.uber.org/goleak v1.1.12
+	gopkg.in/yaml.v2 v2.4.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+)
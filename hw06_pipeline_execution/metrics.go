@@ -0,0 +1,346 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets - границы гистограммы задержек по умолчанию.
+var DefaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	time.Millisecond * 5,
+	time.Millisecond * 10,
+	time.Millisecond * 50,
+	time.Millisecond * 100,
+	time.Millisecond * 500,
+	time.Second,
+	time.Second * 5,
+}
+
+// LatencyHistogram - гистограмма задержек этапа.
+type LatencyHistogram struct {
+	Buckets []time.Duration // верхние границы интервалов
+	Counts  []uint64        // количество задержек не больше каждой границы, накопительно
+	Count   uint64          // всего задержек, в т.ч. больше последней границы
+	Sum     time.Duration   // сумма задержек
+}
+
+// StageStats - снимок метрик этапа.
+type StageStats struct {
+	Name       string
+	In         uint64  // значений получено
+	Out        uint64  // значений отправлено
+	InRate     float64 // значений в секунду на входе за время работы этапа
+	OutRate    float64 // значений в секунду на выходе за время работы этапа
+	QueueDepth int64   // значений ждут приёма этапом
+	InFlight   int64   // значений принято этапом, но ещё не отправлено, только для Instrument
+	Latency    LatencyHistogram
+}
+
+// Span - время обработки значения этапом, от приёма до отправки.
+type Span struct {
+	Stage string
+	Start time.Time
+	End   time.Time
+}
+
+// Metrics собирает метрики этапов, обёрнутых через Instrument или InstrumentThroughput.
+type Metrics struct {
+	Namespace string          // префикс имён метрик Prometheus
+	Buckets   []time.Duration // границы гистограммы задержек, nil - DefaultLatencyBuckets
+	OnSpan    func(Span)      // трассировка обработки каждого значения, может быть nil
+
+	mutex  sync.Mutex
+	stages []*stageMetrics
+}
+
+// ограничение количества запомненных моментов приёма значений этапом.
+const maxTaken = 1 << 16
+
+// метрики этапов с одним именем по всем их запускам.
+type stageMetrics struct {
+	mutex    sync.Mutex
+	name     string
+	running  int           // запусков этапа работает сейчас
+	started  time.Time     // начало работы текущих запусков
+	active   time.Duration // время работы завершённых запусков, без перерывов между ними
+	in       uint64
+	out      uint64
+	queued   int64
+	inFlight int64
+	latency  LatencyHistogram
+}
+
+// один запуск этапа, значения сопоставляются только внутри него.
+type stageRun struct {
+	sm     *stageMetrics
+	paired bool        // этап отправляет по одному значению на каждое принятое
+	taken  []time.Time // время приёма значений, ещё не отправленных этапом
+}
+
+// Instrument оборачивает этап, собирая его метрики под именем name.
+// Этап должен отправлять по одному значению на каждое принятое и в том же порядке (Map, Throttle):
+// по этим парам считаются задержки, спаны и InFlight.
+// Этапы с одинаковыми именами и повторные запуски учитываются вместе, пары значений - в каждом запуске отдельно.
+func (m *Metrics) Instrument(name string, stage Stage) Stage {
+	return m.instrument(name, stage, true)
+}
+
+// InstrumentThroughput оборачивает этап, собирая под именем name только количества и скорости
+// значений на входе и выходе. Подходит для этапов, не сохраняющих соответствие
+// принятых и отправленных значений (Filter, FlatMap, Batch).
+func (m *Metrics) InstrumentThroughput(name string, stage Stage) Stage {
+	return m.instrument(name, stage, false)
+}
+
+func (m *Metrics) instrument(name string, stage Stage, paired bool) Stage {
+	if stage == nil {
+		return nil
+	}
+
+	return func(in In) Out {
+		run := m.stage(name).start(paired)
+
+		stageIn := make(Bi)
+		go func() {
+			defer close(stageIn)
+			for v := range in {
+				run.received()
+				stageIn <- v
+				run.accepted()
+			}
+		}()
+
+		stageOut := stage(stageIn)
+
+		out := make(Bi)
+		go func() {
+			defer close(out)
+			defer run.finished()
+
+			for v := range stageOut {
+				if span, ok := run.emitted(); ok && m.OnSpan != nil {
+					m.OnSpan(span)
+				}
+				out <- v
+			}
+		}()
+
+		return out
+	}
+}
+
+// InstrumentAll оборачивает этапы через Instrument для ExecutePipeline, называя их stage_0, stage_1 и т.д.
+func (m *Metrics) InstrumentAll(stages ...Stage) []Stage {
+	instrumented := make([]Stage, 0, len(stages))
+	for i, stage := range stages {
+		instrumented = append(instrumented, m.Instrument("stage_"+strconv.Itoa(i), stage))
+	}
+
+	return instrumented
+}
+
+// Snapshot возвращает метрики этапов в порядке их запуска.
+func (m *Metrics) Snapshot() []StageStats {
+	m.mutex.Lock()
+	stages := append([]*stageMetrics(nil), m.stages...)
+	m.mutex.Unlock()
+
+	now := time.Now()
+	snapshot := make([]StageStats, 0, len(stages))
+
+	for _, sm := range stages {
+		sm.mutex.Lock()
+
+		stats := StageStats{
+			Name:       sm.name,
+			In:         sm.in,
+			Out:        sm.out,
+			QueueDepth: sm.queued,
+			InFlight:   sm.inFlight,
+			Latency: LatencyHistogram{
+				Buckets: sm.latency.Buckets,
+				Counts:  append([]uint64(nil), sm.latency.Counts...),
+				Count:   sm.latency.Count,
+				Sum:     sm.latency.Sum,
+			},
+		}
+		elapsed := sm.active
+		if sm.running > 0 {
+			elapsed += now.Sub(sm.started)
+		}
+		if elapsed > 0 {
+			stats.InRate = float64(sm.in) / elapsed.Seconds()
+			stats.OutRate = float64(sm.out) / elapsed.Seconds()
+		}
+
+		sm.mutex.Unlock()
+
+		snapshot = append(snapshot, stats)
+	}
+
+	return snapshot
+}
+
+// WriteTo выводит метрики этапов в w в текстовом формате Prometheus с меткой stage.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	snapshot := m.Snapshot()
+
+	var buf bytes.Buffer
+
+	writeHeader := func(name, kind, help string) string {
+		name = m.Namespace + "_" + name
+		fmt.Fprintf(&buf, "# HELP %s %s\n", name, help)
+		fmt.Fprintf(&buf, "# TYPE %s %s\n", name, kind)
+		return name
+	}
+
+	writeMetric := func(name, kind, help string, value func(stats StageStats) interface{}) {
+		name = writeHeader(name, kind, help)
+		for _, stats := range snapshot {
+			fmt.Fprintf(&buf, "%s{stage=%q} %v\n", name, stats.Name, value(stats))
+		}
+	}
+
+	writeMetric("stage_items_in_total", "counter", "Number of items received by the stage.",
+		func(stats StageStats) interface{} { return stats.In })
+	writeMetric("stage_items_out_total", "counter", "Number of items sent by the stage.",
+		func(stats StageStats) interface{} { return stats.Out })
+	writeMetric("stage_queue_depth", "gauge", "Number of items waiting to be accepted by the stage.",
+		func(stats StageStats) interface{} { return stats.QueueDepth })
+	writeMetric("stage_in_flight", "gauge", "Number of items accepted by the stage but not sent yet.",
+		func(stats StageStats) interface{} { return stats.InFlight })
+
+	name := writeHeader("stage_latency_seconds", "histogram", "Time from accepting an item to sending a result.")
+	for _, stats := range snapshot {
+		for i, bound := range stats.Latency.Buckets {
+			fmt.Fprintf(&buf, "%s_bucket{stage=%q,le=\"%v\"} %d\n", name, stats.Name, bound.Seconds(), stats.Latency.Counts[i])
+		}
+		fmt.Fprintf(&buf, "%s_bucket{stage=%q,le=\"+Inf\"} %d\n", name, stats.Name, stats.Latency.Count)
+		fmt.Fprintf(&buf, "%s_sum{stage=%q} %v\n", name, stats.Name, stats.Latency.Sum.Seconds())
+		fmt.Fprintf(&buf, "%s_count{stage=%q} %d\n", name, stats.Name, stats.Latency.Count)
+	}
+
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP отдаёт метрики этапов, чтобы их можно было зарегистрировать как /metrics.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = m.WriteTo(w)
+}
+
+// найти или зарегистрировать метрики этапа.
+func (m *Metrics) stage(name string) *stageMetrics {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for _, sm := range m.stages {
+		if sm.name == name {
+			return sm
+		}
+	}
+
+	buckets := m.Buckets
+	if buckets == nil {
+		buckets = DefaultLatencyBuckets
+	}
+
+	sm := &stageMetrics{
+		name:    name,
+		latency: LatencyHistogram{Buckets: buckets, Counts: make([]uint64, len(buckets))},
+	}
+	m.stages = append(m.stages, sm)
+
+	return sm
+}
+
+// начать запуск этапа.
+func (sm *stageMetrics) start(paired bool) *stageRun {
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	if sm.running == 0 {
+		sm.started = time.Now()
+	}
+	sm.running++
+
+	return &stageRun{sm: sm, paired: paired}
+}
+
+// значение получено от предыдущего этапа.
+func (run *stageRun) received() {
+	sm := run.sm
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.in++
+	sm.queued++
+}
+
+// значение принято этапом.
+func (run *stageRun) accepted() {
+	sm := run.sm
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.queued--
+	if !run.paired {
+		return
+	}
+
+	// этап, ошибочно обёрнутый через Instrument, не должен копить память
+	if len(run.taken) == maxTaken {
+		run.taken = run.taken[1:]
+		sm.inFlight--
+	}
+	run.taken = append(run.taken, time.Now())
+	sm.inFlight++
+}
+
+// этап отправил значение, вернуть время его обработки, если есть парное принятое.
+func (run *stageRun) emitted() (Span, bool) {
+	sm := run.sm
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.out++
+	if len(run.taken) == 0 {
+		return Span{}, false
+	}
+
+	span := Span{Stage: sm.name, Start: run.taken[0], End: time.Now()}
+	run.taken = run.taken[1:]
+	sm.inFlight--
+
+	latency := span.End.Sub(span.Start)
+	for i, bound := range sm.latency.Buckets {
+		if latency <= bound {
+			sm.latency.Counts[i]++
+		}
+	}
+	sm.latency.Count++
+	sm.latency.Sum += latency
+
+	return span, true
+}
+
+// запуск завершился, принятые им значения больше не в обработке.
+func (run *stageRun) finished() {
+	sm := run.sm
+	sm.mutex.Lock()
+	defer sm.mutex.Unlock()
+
+	sm.inFlight -= int64(len(run.taken))
+	run.taken = nil
+
+	sm.running--
+	if sm.running == 0 {
+		sm.active += time.Since(sm.started)
+	}
+}
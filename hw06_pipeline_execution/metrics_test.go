@@ -0,0 +1,217 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	slow := Stage(Map(func(v interface{}) interface{} {
+		time.Sleep(time.Millisecond * 20)
+		return v
+	}))
+	odd := Stage(Filter(func(v interface{}) bool { return v.(int)%2 != 0 }))
+
+	source := func(data ...int) In {
+		in := make(Bi)
+		go func() {
+			defer close(in)
+			for _, v := range data {
+				in <- v
+			}
+		}()
+		return in
+	}
+
+	t.Run("snapshot", func(t *testing.T) {
+		var mutex sync.Mutex
+		var spans []Span
+
+		m := &Metrics{
+			Buckets: []time.Duration{time.Millisecond * 10, time.Second},
+			OnSpan: func(span Span) {
+				mutex.Lock()
+				spans = append(spans, span)
+				mutex.Unlock()
+			},
+		}
+
+		result := make([]int, 0, 10)
+		stages := []Stage{m.InstrumentThroughput("stage_0", odd), m.Instrument("stage_1", slow)}
+		for v := range ExecutePipeline(source(1, 2, 3, 4), nil, stages...) {
+			result = append(result, v.(int))
+		}
+		require.Equal(t, []int{1, 3}, result)
+
+		snapshot := m.Snapshot()
+		require.Len(t, snapshot, 2)
+
+		filter, mapper := snapshot[0], snapshot[1]
+
+		require.Equal(t, "stage_0", filter.Name)
+		require.Equal(t, uint64(4), filter.In)
+		require.Equal(t, uint64(2), filter.Out)
+		require.Zero(t, filter.InFlight)
+		require.Zero(t, filter.Latency.Count)
+		require.Zero(t, filter.QueueDepth)
+
+		require.Equal(t, "stage_1", mapper.Name)
+		require.Equal(t, uint64(2), mapper.In)
+		require.Equal(t, uint64(2), mapper.Out)
+		require.Greater(t, mapper.OutRate, 0.0)
+		require.Equal(t, uint64(2), mapper.Latency.Count)
+		require.Equal(t, []uint64{0, 2}, mapper.Latency.Counts)
+		require.GreaterOrEqual(t, int64(mapper.Latency.Sum), int64(time.Millisecond*40))
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		stageSpans := 0
+		for _, span := range spans {
+			if span.Stage == "stage_1" {
+				stageSpans++
+				require.GreaterOrEqual(t, int64(span.End.Sub(span.Start)), int64(time.Millisecond*20))
+			}
+		}
+		require.Equal(t, 2, stageSpans)
+	})
+
+	t.Run("in flight", func(t *testing.T) {
+		m := &Metrics{}
+
+		block := make(chan struct{})
+		blocking := Stage(Map(func(v interface{}) interface{} {
+			<-block
+			return v
+		}))
+
+		out := ExecutePipeline(source(1, 2), nil, m.Instrument("blocking", blocking))
+
+		require.Eventually(t, func() bool {
+			stats := m.Snapshot()
+			return len(stats) == 1 && stats[0].InFlight == 1 && stats[0].QueueDepth == 1
+		}, time.Second, time.Millisecond)
+
+		close(block)
+		for range out {
+		}
+
+		stats := m.Snapshot()[0]
+		require.Zero(t, stats.InFlight)
+		require.Equal(t, uint64(2), stats.Out)
+	})
+
+	t.Run("filter in flight", func(t *testing.T) {
+		m := &Metrics{}
+
+		// вход не закрывается: этап работает, пока проверяются метрики
+		in := make(Bi)
+		defer close(in)
+
+		out := ExecutePipeline(in, nil, m.InstrumentThroughput("odd", odd))
+		for i := 1; i <= 4; i++ {
+			in <- i
+		}
+		require.Equal(t, 1, <-out)
+		require.Equal(t, 3, <-out)
+
+		// приём последнего значения учитывается уже после его отправки этапу
+		settled := func() bool {
+			stats := m.Snapshot()[0]
+			return stats.In == 4 && stats.QueueDepth == 0
+		}
+		for deadline := time.Now().Add(time.Second); !settled() && time.Now().Before(deadline); {
+			time.Sleep(time.Millisecond)
+		}
+
+		stats := m.Snapshot()[0]
+		require.Equal(t, uint64(4), stats.In)
+		require.Equal(t, uint64(2), stats.Out)
+		require.Zero(t, stats.InFlight)
+		require.Zero(t, stats.QueueDepth)
+	})
+
+	t.Run("same name", func(t *testing.T) {
+		m := &Metrics{}
+
+		block := make(chan struct{})
+		blocking := Stage(Map(func(v interface{}) interface{} {
+			<-block
+			return v
+		}))
+
+		// первый этап держит значение, второй с тем же именем успевает завершиться
+		blocked := ExecutePipeline(source(1), nil, m.Instrument("shared", blocking))
+		require.Eventually(t, func() bool {
+			stats := m.Snapshot()
+			return len(stats) == 1 && stats[0].InFlight == 1
+		}, time.Second, time.Millisecond)
+
+		for range ExecutePipeline(source(2, 3), nil, m.Instrument("shared", slow)) {
+		}
+
+		stats := m.Snapshot()
+		require.Len(t, stats, 1)
+		require.Equal(t, uint64(3), stats[0].In)
+		require.Equal(t, uint64(2), stats[0].Out)
+		require.Equal(t, int64(1), stats[0].InFlight)
+		require.Equal(t, uint64(2), stats[0].Latency.Count)
+
+		close(block)
+		for range blocked {
+		}
+
+		stats = m.Snapshot()
+		require.Zero(t, stats[0].InFlight)
+		require.Equal(t, uint64(3), stats[0].Latency.Count)
+	})
+
+	t.Run("rate over runs", func(t *testing.T) {
+		m := &Metrics{}
+		stage := m.Instrument("slow", slow)
+
+		var busy time.Duration
+		for i := 0; i < 2; i++ {
+			start := time.Now()
+			for range ExecutePipeline(source(1, 2), nil, stage) {
+			}
+			busy += time.Since(start)
+
+			// простой между запусками в скорость не входит
+			time.Sleep(time.Millisecond * 100)
+		}
+
+		stats := m.Snapshot()[0]
+		require.Equal(t, uint64(4), stats.Out)
+		require.GreaterOrEqual(t, stats.OutRate, 4/busy.Seconds())
+	})
+
+	t.Run("prometheus", func(t *testing.T) {
+		m := &Metrics{Namespace: "pipeline", Buckets: []time.Duration{time.Second}}
+
+		for range ExecutePipeline(source(1, 2, 3), nil, m.Instrument("slow", slow)) {
+		}
+
+		var buf bytes.Buffer
+		_, err := m.WriteTo(&buf)
+		require.NoError(t, err)
+
+		text := buf.String()
+		require.Contains(t, text, "# TYPE pipeline_stage_items_in_total counter\n")
+		require.Contains(t, text, "pipeline_stage_items_in_total{stage=\"slow\"} 3\n")
+		require.Contains(t, text, "pipeline_stage_items_out_total{stage=\"slow\"} 3\n")
+		require.Contains(t, text, "# TYPE pipeline_stage_latency_seconds histogram\n")
+		require.Contains(t, text, "pipeline_stage_latency_seconds_bucket{stage=\"slow\",le=\"1\"} 3\n")
+		require.Contains(t, text, "pipeline_stage_latency_seconds_bucket{stage=\"slow\",le=\"+Inf\"} 3\n")
+		require.Contains(t, text, "pipeline_stage_latency_seconds_count{stage=\"slow\"} 3\n")
+
+		recorder := httptest.NewRecorder()
+		m.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+		require.Equal(t, text, recorder.Body.String())
+	})
+}
@@ -0,0 +1,129 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import "sync"
+
+// ParallelOptions - параметры параллельного этапа.
+type ParallelOptions struct {
+	Workers int  // количество воркеров, <= 0 - один воркер
+	Ordered bool // сохранять порядок входных значений
+}
+
+// значение с порядковым номером во входном канале.
+type sequenced[T any] struct {
+	seq   int
+	value T
+}
+
+// FanOut запускает workers копий этапа, раздавая им входные значения, и объединяет их выходы.
+// Порядок значений на выходе не сохраняется.
+func FanOut(workers int, stage Stage) Stage {
+	if workers <= 1 {
+		return stage
+	}
+
+	return func(in In) Out {
+		out := make(Bi)
+
+		wg := sync.WaitGroup{}
+		wg.Add(workers)
+
+		for i := 0; i < workers; i++ {
+			// копии читают общий вход, свободная копия забирает очередное значение
+			go func(stageOut Out) {
+				defer wg.Done()
+				for v := range stageOut {
+					out <- v
+				}
+			}(stage(in))
+		}
+
+		go func() {
+			wg.Wait()
+			close(out)
+		}()
+
+		return out
+	}
+}
+
+// ParallelMap возвращает этап, применяющий f к значениям в opts.Workers горутинах.
+// С opts.Ordered значения выходят в порядке поступления, для этого в обработке
+// одновременно не больше 2*Workers значений.
+// Для ExecutePipeline с I и O interface{} результат приводится к Stage.
+func ParallelMap[I, O any](opts ParallelOptions, f func(v I) O) TypedStage[I, O] {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	return func(in <-chan I) <-chan O {
+		jobs := make(chan sequenced[I])
+		results := make(chan sequenced[O])
+
+		// ограничение числа значений в обработке, чтобы буфер упорядочивания не рос
+		var slots chan struct{}
+		if opts.Ordered {
+			slots = make(chan struct{}, workers*2)
+		}
+
+		go func() {
+			defer close(jobs)
+			seq := 0
+			for v := range in {
+				if slots != nil {
+					slots <- struct{}{}
+				}
+				jobs <- sequenced[I]{seq: seq, value: v}
+				seq++
+			}
+		}()
+
+		wg := sync.WaitGroup{}
+		wg.Add(workers)
+
+		for i := 0; i < workers; i++ {
+			go func() {
+				defer wg.Done()
+				for job := range jobs {
+					results <- sequenced[O]{seq: job.seq, value: f(job.value)}
+				}
+			}()
+		}
+
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		out := make(chan O)
+
+		go func() {
+			defer close(out)
+
+			if !opts.Ordered {
+				for result := range results {
+					out <- result.value
+				}
+				return
+			}
+
+			// результаты, обогнавшие очередное по порядку значение
+			pending := make(map[int]O, workers*2)
+			next := 0
+
+			for result := range results {
+				pending[result.seq] = result.value
+
+				for value, ok := pending[next]; ok; value, ok = pending[next] {
+					delete(pending, next)
+					next++
+
+					out <- value
+					<-slots
+				}
+			}
+		}()
+
+		return out
+	}
+}
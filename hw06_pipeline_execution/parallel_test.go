@@ -0,0 +1,110 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallel(t *testing.T) {
+	data := make([]int, 20)
+	for i := range data {
+		data[i] = i
+	}
+
+	t.Run("fan out", func(t *testing.T) {
+		in := make(Bi)
+		go func() {
+			defer close(in)
+			for _, v := range data {
+				in <- v
+			}
+		}()
+
+		slow := Untyped(typedStage(func(v int) int { return v * 2 }))
+
+		result := make([]int, 0, len(data))
+		start := time.Now()
+		for v := range ExecutePipeline(in, nil, FanOut(10, slow)) {
+			result = append(result, v.(int))
+		}
+		elapsed := time.Since(start)
+
+		sort.Ints(result)
+		for i, v := range result {
+			require.Equal(t, data[i]*2, v)
+		}
+		// 20 значений по 100ms в 10 копиях
+		require.Less(t, int64(elapsed), int64(sleepPerStage)*int64(len(data)/10)+int64(fault))
+	})
+
+	t.Run("ordered", func(t *testing.T) {
+		stage := ParallelMap(ParallelOptions{Workers: 5, Ordered: true}, func(v int) string {
+			time.Sleep(time.Duration(rand.Intn(10)) * time.Millisecond) //nolint:gosec
+			return strconv.Itoa(v)
+		})
+
+		result := make([]string, 0, len(data))
+		for s := range Then(NewPipeline[int](), stage).Run(generate(data), nil) {
+			result = append(result, s)
+		}
+
+		expected := make([]string, 0, len(data))
+		for _, v := range data {
+			expected = append(expected, strconv.Itoa(v))
+		}
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("unordered", func(t *testing.T) {
+		stage := ParallelMap(ParallelOptions{Workers: 10}, func(v interface{}) interface{} {
+			time.Sleep(sleepPerStage)
+			return v.(int) + 1
+		})
+
+		in := make(Bi)
+		go func() {
+			defer close(in)
+			for _, v := range data {
+				in <- v
+			}
+		}()
+
+		result := make([]int, 0, len(data))
+		start := time.Now()
+		for v := range ExecutePipeline(in, nil, Stage(stage)) {
+			result = append(result, v.(int))
+		}
+		elapsed := time.Since(start)
+
+		require.Len(t, result, len(data))
+		require.Less(t, int64(elapsed), int64(sleepPerStage)*int64(len(data)/10)+int64(fault))
+	})
+
+	t.Run("done case", func(t *testing.T) {
+		done := make(Bi)
+		go func() {
+			<-time.After(fault)
+			close(done)
+		}()
+
+		stage := ParallelMap(ParallelOptions{Workers: 3, Ordered: true}, func(v int) int {
+			time.Sleep(sleepPerStage)
+			return v
+		})
+
+		result := make([]int, 0, len(data))
+		start := time.Now()
+		for v := range Then(NewPipeline[int](), stage).Pipe(typedStage(func(v int) int { return v })).Run(generate(data), done) {
+			result = append(result, v)
+		}
+		elapsed := time.Since(start)
+
+		require.Empty(t, result)
+		require.Less(t, int64(elapsed), int64(sleepPerStage)*2+int64(fault))
+	})
+}
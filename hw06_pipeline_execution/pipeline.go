@@ -8,38 +8,97 @@ type (
 
 type Stage func(in In) (out Out)
 
-// добавляет перед каждым этапом конвеера обработку канала прерывания.
-func addInterruptor(in In, done In, stageToRun Stage) Out {
-	stageIn := make(Bi)
+// сигнал остановки конвеера: закрытие done или отмена контекста.
+type stopSignal struct {
+	done In
+	ctx  <-chan struct{}
+}
 
-	go func() {
-		defer close(stageIn)
-		for {
-			select {
-			case <-done:
-				return
-			case n, good := <-in:
-				if !good {
-					return
-				}
-				stageIn <- n
-			}
-		}
-	}()
+// добавляет перед каждым этапом конвеера обработку сигнала остановки и буфер.
+// Значения с выхода предыдущего этапа после остановки дочитываются, чтобы он мог завершиться.
+func addInterruptor(in In, stop stopSignal, drain bool, buffer BufferOptions, stageToRun Stage) Out {
+	stageInput := interrupt(in, stop, drain)
 
-	return stageToRun(stageIn)
+	// остаток буфера после остановки не обрабатывается этапом, а дочитывается
+	if buffer.Size > 0 {
+		stageInput = interrupt(Buffer[interface{}](buffer)(stageInput), stop, true)
+	}
+
+	return stageToRun(stageInput)
 }
 
 func ExecutePipeline(in In, done In, stages ...Stage) Out {
+	return executeStages(in, stopSignal{done: done}, nil, stages)
+}
+
+// ExecutePipelineBuffered запускает конвеер как ExecutePipeline, ставя перед этапом stages[i]
+// буфер buffers[i]. Этапы без буфера в buffers получают значения без буферизации.
+func ExecutePipelineBuffered(in In, done In, buffers []BufferOptions, stages ...Stage) Out {
+	return executeStages(in, stopSignal{done: done}, buffers, stages)
+}
+
+func executeStages(in In, stop stopSignal, buffers []BufferOptions, stages []Stage) Out {
 	nextStageInput := in
 
-	for _, stage := range stages {
+	for i, stage := range stages {
 		if stage == nil {
 			continue
 		}
 
-		nextStageInput = addInterruptor(nextStageInput, done, stage)
+		var buffer BufferOptions
+		if i < len(buffers) {
+			buffer = buffers[i]
+		}
+
+		// вход конвеера не дочитываем: источник может быть бесконечным
+		nextStageInput = addInterruptor(nextStageInput, stop, nextStageInput != in, buffer, stage)
 	}
 
-	return nextStageInput
+	if nextStageInput == in {
+		return in
+	}
+
+	// выход последнего этапа тоже прерывается, даже если его перестали читать
+	return interrupt(nextStageInput, stop, true)
+}
+
+// interrupt передаёт значения из in, пока не закроется in или не сработает stop,
+// ожидая остановку и при чтении, и при отправке.
+// С drain после остановки дочитывает in до закрытия, чтобы не заблокировать его отправителя.
+func interrupt[T any](in <-chan T, stop stopSignal, drain bool) <-chan T {
+	out := make(chan T)
+
+	go func() {
+		// выход закрывается сразу, а дочитывание идёт уже после
+		if drain {
+			defer func() {
+				for range in {
+				}
+			}()
+		}
+		defer close(out)
+
+		for {
+			select {
+			case <-stop.done:
+				return
+			case <-stop.ctx:
+				return
+			case n, good := <-in:
+				if !good {
+					return
+				}
+
+				select {
+				case out <- n:
+				case <-stop.done:
+					return
+				case <-stop.ctx:
+					return
+				}
+			}
+		}
+	}()
+
+	return out
 }
@@ -0,0 +1,277 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import "time"
+
+// Этапы ниже работают в одной горутине и завершаются, когда закрывается их вход,
+// поэтому прерываются вместе с конвеером. Для ExecutePipeline этапы с входом и выходом
+// interface{} приводятся к Stage, остальные оборачиваются в Untyped.
+
+// Map применяет f к каждому значению.
+func Map[I, O any](f func(v I) O) TypedStage[I, O] {
+	return func(in <-chan I) <-chan O {
+		out := make(chan O)
+		go func() {
+			defer close(out)
+			for v := range in {
+				out <- f(v)
+			}
+		}()
+		return out
+	}
+}
+
+// Filter пропускает значения, для которых keep возвращает true.
+func Filter[T any](keep func(v T) bool) TypedStage[T, T] {
+	return func(in <-chan T) <-chan T {
+		out := make(chan T)
+		go func() {
+			defer close(out)
+			for v := range in {
+				if keep(v) {
+					out <- v
+				}
+			}
+		}()
+		return out
+	}
+}
+
+// FlatMap отправляет по очереди все значения, которые f вернула для входного.
+func FlatMap[I, O any](f func(v I) []O) TypedStage[I, O] {
+	return func(in <-chan I) <-chan O {
+		out := make(chan O)
+		go func() {
+			defer close(out)
+			for v := range in {
+				for _, result := range f(v) {
+					out <- result
+				}
+			}
+		}()
+		return out
+	}
+}
+
+// Batch собирает значения в пачки по size штук. Неполная пачка отправляется,
+// если с её первого значения прошло maxWait (<= 0 - без ограничения), и при закрытии входа.
+func Batch[T any](size int, maxWait time.Duration) TypedStage[T, []T] {
+	if size <= 0 {
+		size = 1
+	}
+
+	return func(in <-chan T) <-chan []T {
+		out := make(chan []T)
+		go func() {
+			defer close(out)
+
+			var batch []T
+			var timer *time.Timer
+			var timeout <-chan time.Time
+
+			flush := func() {
+				if timer != nil {
+					timer.Stop()
+					timer, timeout = nil, nil
+				}
+				if len(batch) > 0 {
+					out <- batch
+					batch = nil
+				}
+			}
+
+			for {
+				select {
+				case v, ok := <-in:
+					if !ok {
+						flush()
+						return
+					}
+
+					if len(batch) == 0 && maxWait > 0 {
+						timer = time.NewTimer(maxWait)
+						timeout = timer.C
+					}
+
+					batch = append(batch, v)
+					if len(batch) == size {
+						flush()
+					}
+				case <-timeout:
+					timer, timeout = nil, nil
+					flush()
+				}
+			}
+		}()
+		return out
+	}
+}
+
+// minWindowStep - наименьший шаг окна, к нему приводятся неположительные size и step.
+const minWindowStep = time.Millisecond
+
+// TumblingWindow отправляет значения, пришедшие за очередной интервал size.
+// Интервалы не пересекаются, пустые окна не отправляются.
+func TumblingWindow[T any](size time.Duration) TypedStage[T, []T] {
+	return SlidingWindow[T](size, size)
+}
+
+// SlidingWindow каждые step отправляет значения, пришедшие за последние size,
+// округлённые вверх до целого числа шагов. При step < size окна пересекаются,
+// и значение попадает в несколько окон. Окна без новых значений не отправляются,
+// при закрытии входа отправляется последнее окно. step <= 0 - шаг равен size,
+// если и size <= 0 - шаг minWindowStep.
+func SlidingWindow[T any](size, step time.Duration) TypedStage[T, []T] {
+	if step <= 0 {
+		step = size
+	}
+	if step <= 0 {
+		step = minWindowStep
+	}
+
+	steps := int((size + step - 1) / step)
+	if steps < 1 {
+		steps = 1
+	}
+
+	return func(in <-chan T) <-chan []T {
+		out := make(chan []T)
+		go func() {
+			defer close(out)
+
+			ticker := time.NewTicker(step)
+			defer ticker.Stop()
+
+			// значения по шагам, последний - текущий шаг
+			buckets := make([][]T, 1, steps)
+
+			emit := func() {
+				if len(buckets[len(buckets)-1]) == 0 {
+					return
+				}
+
+				var window []T
+				for _, bucket := range buckets {
+					window = append(window, bucket...)
+				}
+				out <- window
+			}
+
+			for {
+				select {
+				case v, ok := <-in:
+					if !ok {
+						emit()
+						return
+					}
+					buckets[len(buckets)-1] = append(buckets[len(buckets)-1], v)
+				case <-ticker.C:
+					emit()
+
+					if len(buckets) == steps {
+						buckets = append(buckets[:0], buckets[1:]...)
+					}
+					buckets = append(buckets, nil)
+				}
+			}
+		}()
+		return out
+	}
+}
+
+// Throttle пропускает не больше rate значений в секунду, задерживая остальные.
+// Закрытие done прерывает ожидание и останавливает этап, nil - без остановки.
+func Throttle[T any](done <-chan struct{}, rate float64) TypedStage[T, T] {
+	return func(in <-chan T) <-chan T {
+		out := make(chan T)
+		go func() {
+			// после остановки вход дочитывается, чтобы не заблокировать отправителя
+			defer func() {
+				for range in {
+				}
+			}()
+			defer close(out)
+
+			var interval time.Duration
+			if rate > 0 {
+				interval = time.Duration(float64(time.Second) / rate)
+			}
+
+			timer := time.NewTimer(0)
+			defer timer.Stop()
+
+			for v := range in {
+				// таймер отсчитывает интервал от предыдущей отправки
+				select {
+				case <-timer.C:
+				case <-done:
+					return
+				}
+
+				select {
+				case out <- v:
+				case <-done:
+					return
+				}
+
+				timer.Reset(interval)
+			}
+		}()
+		return out
+	}
+}
+
+// Dedupe пропускает только первое значение с каждым ключом.
+// Ключи хранятся всё время работы этапа.
+func Dedupe[T any, K comparable](key func(v T) K) TypedStage[T, T] {
+	return func(in <-chan T) <-chan T {
+		out := make(chan T)
+		go func() {
+			defer close(out)
+
+			seen := make(map[K]struct{})
+			for v := range in {
+				k := key(v)
+				if _, ok := seen[k]; ok {
+					continue
+				}
+				seen[k] = struct{}{}
+
+				out <- v
+			}
+		}()
+		return out
+	}
+}
+
+// Tee передаёт значения дальше и копию каждого в side, закрывая side по завершении этапа.
+// side нужно читать до закрытия, иначе этап остановится на отправке в него.
+// Закрытие done прерывает отправку и останавливает этап, nil - без остановки.
+func Tee[T any](done <-chan struct{}, side chan<- T) TypedStage[T, T] {
+	return func(in <-chan T) <-chan T {
+		out := make(chan T)
+		go func() {
+			// после остановки вход дочитывается, чтобы не заблокировать отправителя
+			defer func() {
+				for range in {
+				}
+			}()
+			defer close(out)
+			defer close(side)
+
+			for v := range in {
+				select {
+				case side <- v:
+				case <-done:
+					return
+				}
+
+				select {
+				case out <- v:
+				case <-done:
+					return
+				}
+			}
+		}()
+		return out
+	}
+}
@@ -0,0 +1,199 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+func collectTyped[T any](out <-chan T) []T {
+	result := make([]T, 0, 10)
+	for v := range out {
+		result = append(result, v)
+	}
+	return result
+}
+
+func TestStages(t *testing.T) {
+	t.Run("map filter flat map", func(t *testing.T) {
+		pipeline := Then(
+			Then(NewPipeline[int]().Pipe(Filter(func(v int) bool { return v%2 == 0 })), Map(strconv.Itoa)),
+			FlatMap(func(s string) []rune { return []rune(s) }),
+		)
+
+		result := collectTyped(pipeline.Run(generate([]int{1, 2, 10, 13, 24}), nil))
+		require.Equal(t, []rune("21024"), result)
+	})
+
+	t.Run("untyped", func(t *testing.T) {
+		in := make(Bi)
+		go func() {
+			defer close(in)
+			for _, v := range []interface{}{"a", "b", "a", "c", "b"} {
+				in <- v
+			}
+		}()
+
+		upper := Map(func(v interface{}) interface{} { return strings.ToUpper(v.(string)) })
+		dedupe := Dedupe(func(v interface{}) string { return v.(string) })
+
+		result := make([]string, 0, 10)
+		for v := range ExecutePipeline(in, nil, Stage(dedupe), Stage(upper)) {
+			result = append(result, v.(string))
+		}
+		require.Equal(t, []string{"A", "B", "C"}, result)
+	})
+
+	t.Run("batch by size", func(t *testing.T) {
+		out := Then(NewPipeline[int](), Batch[int](2, 0)).Run(generate([]int{1, 2, 3, 4, 5}), nil)
+		require.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, collectTyped(out))
+	})
+
+	t.Run("batch by time", func(t *testing.T) {
+		in := make(chan int)
+		go func() {
+			defer close(in)
+			in <- 1
+			in <- 2
+			time.Sleep(fault)
+			in <- 3
+		}()
+
+		start := time.Now()
+		out := Batch[int](10, fault/5)(in)
+
+		require.Equal(t, []int{1, 2}, <-out)
+		require.Less(t, int64(time.Since(start)), int64(fault))
+		require.Equal(t, []int{3}, <-out)
+
+		_, ok := <-out
+		require.False(t, ok)
+	})
+
+	t.Run("tumbling window", func(t *testing.T) {
+		in := make(chan int)
+		go func() {
+			defer close(in)
+			in <- 1
+			in <- 2
+			time.Sleep(fault * 3 / 2)
+			in <- 3
+		}()
+
+		require.Equal(t, [][]int{{1, 2}, {3}}, collectTyped(TumblingWindow[int](fault)(in)))
+	})
+
+	t.Run("window without size", func(t *testing.T) {
+		in := make(chan int)
+		go func() {
+			defer close(in)
+			in <- 1
+		}()
+
+		require.Equal(t, [][]int{{1}}, collectTyped(SlidingWindow[int](0, 0)(in)))
+		require.Empty(t, collectTyped(TumblingWindow[int](-time.Second)(generate([]int{}))))
+	})
+
+	t.Run("sliding window", func(t *testing.T) {
+		in := make(chan int)
+		go func() {
+			defer close(in)
+			in <- 1
+			time.Sleep(fault * 3 / 2)
+			in <- 2
+			time.Sleep(fault)
+		}()
+
+		// окно в два шага: 2 приходит, пока 1 ещё в окне
+		result := collectTyped(SlidingWindow[int](fault*2, fault)(in))
+		require.Equal(t, [][]int{{1}, {1, 2}}, result)
+	})
+
+	t.Run("throttle", func(t *testing.T) {
+		start := time.Now()
+		result := collectTyped(Throttle[int](nil, 100)(generate([]int{1, 2, 3, 4, 5, 6})))
+		elapsed := time.Since(start)
+
+		require.Equal(t, []int{1, 2, 3, 4, 5, 6}, result)
+		// 5 интервалов по 10ms
+		require.GreaterOrEqual(t, int64(elapsed), int64(time.Millisecond*50))
+	})
+
+	t.Run("throttle done", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		done := make(chan struct{})
+		in := make(chan int)
+		out := Throttle[int](done, 0.01)(in)
+
+		in <- 1
+		require.Equal(t, 1, <-out)
+
+		// второе значение ждёт 100s, закрытие done прерывает ожидание
+		in <- 2
+		close(done)
+		close(in)
+
+		_, ok := <-out
+		require.False(t, ok)
+	})
+
+	t.Run("tee", func(t *testing.T) {
+		side := make(chan int, 10)
+		result := collectTyped(Tee(nil, side)(generate([]int{1, 2, 3})))
+
+		require.Equal(t, []int{1, 2, 3}, result)
+		require.Equal(t, []int{1, 2, 3}, collectTyped(side))
+	})
+
+	t.Run("tee done", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		done := make(chan struct{})
+		side := make(chan int)
+		in := make(chan int)
+		out := Tee(done, side)(in)
+
+		// side никто не читает, закрытие done прерывает отправку
+		in <- 1
+		close(done)
+		in <- 2
+		close(in)
+
+		_, ok := <-out
+		require.False(t, ok)
+		_, ok = <-side
+		require.False(t, ok)
+	})
+
+	t.Run("interrupt", func(t *testing.T) {
+		defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		in := make(Bi)
+		go func() {
+			defer close(in)
+			for i := 0; ; i++ {
+				select {
+				case in <- i:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+
+		out := ExecutePipelineContext(ctx, in,
+			Stage(Throttle[interface{}](ctx.Done(), 1000)),
+			Untyped(Batch[interface{}](3, time.Second)),
+			Stage(Map(func(v interface{}) interface{} { return len(v.([]interface{})) })),
+		)
+		require.Equal(t, 3, <-out)
+		cancel()
+	})
+}
@@ -0,0 +1,77 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+// TypedStage - этап конвеера с типизированными входом и выходом.
+type TypedStage[I, O any] func(in <-chan I) (out <-chan O)
+
+// Pipeline - цепочка типизированных этапов со входом I и выходом O.
+// Собирается через NewPipeline, Then и Pipe, типы соседних этапов проверяются при компиляции.
+type Pipeline[I, O any] struct {
+	run    func(in <-chan I, stop stopSignal) <-chan O
+	stages int
+}
+
+// NewPipeline возвращает пустой конвеер, передающий значения без изменений.
+func NewPipeline[T any]() Pipeline[T, T] {
+	return Pipeline[T, T]{
+		run: func(in <-chan T, stop stopSignal) <-chan T {
+			return in
+		},
+	}
+}
+
+// Then добавляет в конец конвеера этап, меняющий тип значений.
+// Методы в Go не могут иметь своих параметров типа, поэтому это функция.
+func Then[I, M, O any](pipeline Pipeline[I, M], stage TypedStage[M, O]) Pipeline[I, O] {
+	return Pipeline[I, O]{
+		run: func(in <-chan I, stop stopSignal) <-chan O {
+			// вход конвеера не дочитываем: источник может быть бесконечным
+			return stage(interrupt(pipeline.run(in, stop), stop, pipeline.stages > 0))
+		},
+		stages: pipeline.stages + 1,
+	}
+}
+
+// Pipe добавляет в конец конвеера этап, не меняющий тип значений.
+func (pipeline Pipeline[I, O]) Pipe(stage TypedStage[O, O]) Pipeline[I, O] {
+	return Then(pipeline, stage)
+}
+
+// Run запускает конвеер так же, как ExecutePipeline: после закрытия или сигнала done
+// этапы перестают получать значения, и выходной канал закрывается.
+func (pipeline Pipeline[I, O]) Run(in <-chan I, done In) <-chan O {
+	return pipeline.execute(in, stopSignal{done: done})
+}
+
+func (pipeline Pipeline[I, O]) execute(in <-chan I, stop stopSignal) <-chan O {
+	if pipeline.stages == 0 {
+		return pipeline.run(in, stop)
+	}
+
+	return interrupt(pipeline.run(in, stop), stop, true)
+}
+
+// Untyped превращает типизированный этап в Stage для ExecutePipeline.
+// Значения неподходящего типа на входе вызывают панику, как при приведении типа.
+func Untyped[I, O any](stage TypedStage[I, O]) Stage {
+	return func(in In) Out {
+		typedIn := make(chan I)
+
+		go func() {
+			defer close(typedIn)
+			for v := range in {
+				typedIn <- v.(I)
+			}
+		}()
+
+		out := make(Bi)
+
+		go func() {
+			defer close(out)
+			for v := range stage(typedIn) {
+				out <- v
+			}
+		}()
+
+		return out
+	}
+}
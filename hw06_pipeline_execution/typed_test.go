@@ -0,0 +1,104 @@
+package hw06_pipeline_execution //nolint:golint,stylecheck
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// typedStage - генератор типизированных этапов.
+func typedStage[I, O any](f func(v I) O) TypedStage[I, O] {
+	return func(in <-chan I) <-chan O {
+		out := make(chan O)
+		go func() {
+			defer close(out)
+			for v := range in {
+				time.Sleep(sleepPerStage)
+				out <- f(v)
+			}
+		}()
+		return out
+	}
+}
+
+func generate[T any](data []T) <-chan T {
+	in := make(chan T)
+	go func() {
+		defer close(in)
+		for _, v := range data {
+			in <- v
+		}
+	}()
+	return in
+}
+
+func TestTypedPipeline(t *testing.T) {
+	multiplier := typedStage(func(v int) int { return v * 2 })
+	adder := typedStage(func(v int) int { return v + 100 })
+	stringifier := typedStage(strconv.Itoa)
+
+	t.Run("simple case", func(t *testing.T) {
+		pipeline := Then(NewPipeline[int]().Pipe(multiplier).Pipe(adder), stringifier)
+		data := []int{1, 2, 3, 4, 5}
+
+		result := make([]string, 0, 10)
+		start := time.Now()
+		for s := range pipeline.Run(generate(data), nil) {
+			result = append(result, s)
+		}
+		elapsed := time.Since(start)
+
+		require.Equal(t, []string{"102", "104", "106", "108", "110"}, result)
+		require.Less(t, int64(elapsed), int64(sleepPerStage)*int64(3+len(data)-1)+int64(fault))
+	})
+
+	t.Run("empty pipeline", func(t *testing.T) {
+		result := make([]int, 0, 10)
+		for v := range NewPipeline[int]().Run(generate([]int{1, 2, 3}), nil) {
+			result = append(result, v)
+		}
+
+		require.Equal(t, []int{1, 2, 3}, result)
+	})
+
+	t.Run("done case", func(t *testing.T) {
+		done := make(Bi)
+
+		abortDur := sleepPerStage * 2
+		go func() {
+			<-time.After(abortDur)
+			close(done)
+		}()
+
+		pipeline := Then(NewPipeline[int]().Pipe(multiplier).Pipe(adder).Pipe(adder), stringifier)
+
+		result := make([]string, 0, 10)
+		start := time.Now()
+		for s := range pipeline.Run(generate([]int{1, 2, 3, 4, 5}), done) {
+			result = append(result, s)
+		}
+		elapsed := time.Since(start)
+
+		require.Len(t, result, 0)
+		require.Less(t, int64(elapsed), int64(abortDur)+int64(fault))
+	})
+
+	t.Run("untyped", func(t *testing.T) {
+		in := make(Bi)
+		go func() {
+			defer close(in)
+			for _, v := range []int{1, 2, 3} {
+				in <- v
+			}
+		}()
+
+		result := make([]string, 0, 10)
+		for s := range ExecutePipeline(in, nil, Untyped(multiplier), Untyped(stringifier)) {
+			result = append(result, s.(string))
+		}
+
+		require.Equal(t, []string{"2", "4", "6"}, result)
+	})
+}
@@ -1,15 +1,276 @@
 package main
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
 )
 
 var (
 	ErrUnsupportedFile       = errors.New("unsupported file")
 	ErrOffsetExceedsFileSize = errors.New("offset exceeds file size")
+	ErrNegativeOffset        = errors.New("negative offset")
+	ErrNegativeLimit         = errors.New("negative limit")
 )
 
+const (
+	// размер буфера копирования.
+	bufferSize = 32 * 1024
+	// суффикс файла, в который идёт продолжаемое копирование до переименования в итоговый.
+	partSuffix = ".part"
+)
+
+// Options - параметры копирования.
+type Options struct {
+	Offset   int64     // отступ в источнике
+	Limit    int64     // количество копируемых байт, 0 - до конца файла
+	Resume   bool      // продолжить прерванное копирование, проверив уже скопированное
+	Progress io.Writer // вывод прогресс-бара, nil - без прогресса
+}
+
+// Copy копирует limit байт (0 - до конца файла) из fromPath, начиная с offset, в toPath.
 func Copy(fromPath, toPath string, offset, limit int64) error {
-	// Place your code here
+	return CopyFile(fromPath, toPath, Options{Offset: offset, Limit: limit})
+}
+
+// CopyFile копирует данные прямо в toPath, как cp: обычный файл перезаписывается,
+// ссылка разыменовывается, в устройство или канал данные просто пишутся.
+// С opts.Resume данные пишутся во временный файл toPath+".part", который после копирования
+// получает права toPath (новый - 0666 с учётом umask) и переименовывается в toPath,
+// а при ошибке или прерывании остаётся. Копирование продолжится с конца временного файла,
+// если его содержимое совпадает с началом копируемого диапазона, иначе начнётся заново.
+// Если toPath существует и не является обычным файлом, opts.Resume не действует.
+func CopyFile(fromPath, toPath string, opts Options) error {
+	from, err := os.Open(fromPath)
+	if err != nil {
+		return err
+	}
+	defer from.Close()
+
+	count, err := bytesToCopy(from, opts.Offset, opts.Limit)
+	if err != nil {
+		return err
+	}
+
+	source := io.NewSectionReader(from, opts.Offset, count)
+
+	progress := opts.Progress
+	if progress == nil {
+		progress = ioutil.Discard
+	}
+
+	toInfo, err := os.Lstat(toPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if !opts.Resume || toInfo != nil && !toInfo.Mode().IsRegular() {
+		err = copyDirect(source, toPath, progress)
+	} else {
+		err = copyResumable(source, toPath, progress, toInfo)
+	}
+	if err != nil {
+		return fmt.Errorf("copy %s to %s: %w", fromPath, toPath, err)
+	}
+
 	return nil
 }
+
+// скопировать источник прямо в toPath.
+func copyDirect(source *io.SectionReader, toPath string, progress io.Writer) error {
+	// права нового файла как у cp: 0666 с учётом umask
+	to, err := os.OpenFile(toPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o666) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	err = copyData(to, source, 0, progress)
+	if closeErr := to.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// скопировать источник через временный файл, продолжив прерванное копирование.
+// toInfo - существующий обычный файл toPath или nil.
+func copyResumable(source *io.SectionReader, toPath string, progress io.Writer, toInfo os.FileInfo) error {
+	partPath := toPath + partSuffix
+
+	copied, done, err := resumePoint(source, toPath, partPath)
+	if err != nil || done {
+		return err
+	}
+
+	if err := copyToPart(source, partPath, copied, progress); err != nil {
+		return err
+	}
+
+	// временный файл заменяет toPath вместе с его правами
+	if toInfo != nil {
+		if err := os.Chmod(partPath, toInfo.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(partPath, toPath)
+}
+
+// дописать во временный файл данные источника, начиная с copied.
+func copyToPart(source *io.SectionReader, partPath string, copied int64, progress io.Writer) error {
+	// права нового файла как у cp: 0666 с учётом umask
+	part, err := os.OpenFile(partPath, os.O_WRONLY|os.O_CREATE, 0o666) //nolint:gosec
+	if err != nil {
+		return err
+	}
+
+	// за проверенным началом ничего лишнего не остаётся
+	err = part.Truncate(copied)
+	if err == nil {
+		_, err = part.Seek(copied, io.SeekStart)
+	}
+
+	if err == nil {
+		err = copyData(part, source, copied, progress)
+	}
+
+	if err == nil {
+		err = part.Sync()
+	}
+	if closeErr := part.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// записать в w данные источника, начиная с copied, показывая прогресс.
+func copyData(w io.Writer, source *io.SectionReader, copied int64, progress io.Writer) error {
+	bar := newProgressBar(source.Size(), copied, progress)
+	defer bar.Finish()
+
+	buf := make([]byte, bufferSize)
+	_, err := io.CopyBuffer(io.MultiWriter(w, bar), io.NewSectionReader(source, copied, source.Size()-copied), buf)
+
+	return err
+}
+
+// найти, сколько уже скопировано: done - toPath уже полная копия,
+// иначе copied байт проверенного начала лежат во временном файле.
+// Недописанный toPath без подходящего временного файла переименовывается во временный файл,
+// поэтому при новом прерывании toPath уже не существует, а копия продолжается из partPath.
+func resumePoint(source *io.SectionReader, toPath, partPath string) (copied int64, done bool, err error) {
+	toSize, toValid, err := verifiedSize(source, toPath)
+	if err != nil {
+		return 0, false, err
+	}
+	if toValid && toSize == source.Size() {
+		return 0, true, nil
+	}
+
+	partSize, partValid, err := verifiedSize(source, partPath)
+	if err != nil || partValid {
+		return partSize, false, err
+	}
+
+	if !toValid || toSize == 0 {
+		return 0, false, nil
+	}
+
+	if err := os.Rename(toPath, partPath); err != nil {
+		return 0, false, err
+	}
+
+	return toSize, false, nil
+}
+
+// проверить, что файл path совпадает с началом источника, вернуть его размер.
+// Несуществующий файл не совпадает.
+func verifiedSize(source *io.SectionReader, path string) (int64, bool, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, false, err
+	}
+
+	size := info.Size()
+	if !info.Mode().IsRegular() || size > source.Size() {
+		return 0, false, nil
+	}
+
+	equal, err := sameContent(file, io.NewSectionReader(source, 0, size))
+	if err != nil {
+		return 0, false, err
+	}
+
+	return size, equal, nil
+}
+
+// сравнить содержимое двух потоков по блокам, не читая дальше первого различия.
+func sameContent(a, b io.Reader) (bool, error) {
+	bufA := make([]byte, bufferSize)
+	bufB := make([]byte, bufferSize)
+
+	for {
+		n, errA := io.ReadFull(a, bufA)
+		m, errB := io.ReadFull(b, bufB)
+
+		if !bytes.Equal(bufA[:n], bufB[:m]) {
+			return false, nil
+		}
+
+		endA := errors.Is(errA, io.EOF) || errors.Is(errA, io.ErrUnexpectedEOF)
+		endB := errors.Is(errB, io.EOF) || errors.Is(errB, io.ErrUnexpectedEOF)
+
+		switch {
+		case errA != nil && !endA:
+			return false, errA
+		case errB != nil && !endB:
+			return false, errB
+		case endA || endB:
+			return endA == endB, nil
+		}
+	}
+}
+
+// сколько байт копировать из файла: только обычные файлы с известным размером.
+func bytesToCopy(file *os.File, offset, limit int64) (int64, error) {
+	if offset < 0 {
+		return 0, fmt.Errorf("%w: %d", ErrNegativeOffset, offset)
+	}
+	if limit < 0 {
+		return 0, fmt.Errorf("%w: %d", ErrNegativeLimit, limit)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	if !info.Mode().IsRegular() {
+		return 0, fmt.Errorf("%w: %s", ErrUnsupportedFile, file.Name())
+	}
+
+	size := info.Size()
+	if offset > size {
+		return 0, fmt.Errorf("%w: offset %d, size %d", ErrOffsetExceedsFileSize, offset, size)
+	}
+
+	count := size - offset
+	if limit > 0 && limit < count {
+		count = limit
+	}
+
+	return count, nil
+}
@@ -1,7 +1,107 @@
 package main
 
-import "testing"
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
 
 func TestCopy(t *testing.T) {
-	// Place your code here
+	dir, err := ioutil.TempDir("", "hw07")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	to := filepath.Join(dir, "out.txt")
+
+	t.Run("testdata", func(t *testing.T) {
+		tests := []struct {
+			offset, limit int64
+			expected      string
+		}{
+			{0, 0, "out_offset0_limit0.txt"},
+			{0, 10, "out_offset0_limit10.txt"},
+			{0, 1000, "out_offset0_limit1000.txt"},
+			{0, 10000, "out_offset0_limit10000.txt"},
+			{100, 1000, "out_offset100_limit1000.txt"},
+			{6000, 1000, "out_offset6000_limit1000.txt"},
+		}
+
+		for _, tc := range tests {
+			tc := tc
+			t.Run(tc.expected, func(t *testing.T) {
+				require.NoError(t, Copy("testdata/input.txt", to, tc.offset, tc.limit))
+
+				expected, err := ioutil.ReadFile(filepath.Join("testdata", tc.expected))
+				require.NoError(t, err)
+				actual, err := ioutil.ReadFile(to)
+				require.NoError(t, err)
+
+				require.Equal(t, expected, actual)
+			})
+		}
+	})
+
+	t.Run("offset equals file size", func(t *testing.T) {
+		info, err := os.Stat("testdata/input.txt")
+		require.NoError(t, err)
+
+		require.NoError(t, Copy("testdata/input.txt", to, info.Size(), 0))
+
+		actual, err := ioutil.ReadFile(to)
+		require.NoError(t, err)
+		require.Empty(t, actual)
+	})
+
+	t.Run("offset exceeds file size", func(t *testing.T) {
+		info, err := os.Stat("testdata/input.txt")
+		require.NoError(t, err)
+
+		err = Copy("testdata/input.txt", to, info.Size()+1, 0)
+		require.True(t, errors.Is(err, ErrOffsetExceedsFileSize))
+	})
+
+	t.Run("negative offset", func(t *testing.T) {
+		err := Copy("testdata/input.txt", to, -1, 0)
+		require.True(t, errors.Is(err, ErrNegativeOffset))
+	})
+
+	t.Run("negative limit", func(t *testing.T) {
+		err := Copy("testdata/input.txt", to, 0, -1)
+		require.True(t, errors.Is(err, ErrNegativeLimit))
+	})
+
+	t.Run("unsupported file", func(t *testing.T) {
+		err := Copy("/dev/urandom", to, 0, 10)
+		require.True(t, errors.Is(err, ErrUnsupportedFile))
+
+		err = Copy(dir, to, 0, 0)
+		require.True(t, errors.Is(err, ErrUnsupportedFile))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := Copy(filepath.Join(dir, "missing.txt"), to, 0, 0)
+		require.True(t, errors.Is(err, os.ErrNotExist))
+	})
+
+	t.Run("large file", func(t *testing.T) {
+		// больше буфера копирования
+		data := bytes.Repeat([]byte("0123456789"), bufferSize/3)
+		from := filepath.Join(dir, "large.txt")
+		require.NoError(t, ioutil.WriteFile(from, data, 0o600))
+
+		var progress bytes.Buffer
+		require.NoError(t, CopyFile(from, to, Options{Offset: 5, Limit: int64(len(data)), Progress: &progress}))
+
+		actual, err := ioutil.ReadFile(to)
+		require.NoError(t, err)
+		require.Equal(t, data[5:], actual)
+
+		require.True(t, strings.HasSuffix(progress.String(), "] 100%\n"))
+	})
 }
@@ -1,3 +1,5 @@
 module github.com/fixme_my_friend/hw07_file_copying
 
 go 1.15
+
+require github.com/stretchr/testify v1.5.1
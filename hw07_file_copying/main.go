@@ -2,11 +2,14 @@ package main
 
 import (
 	"flag"
+	"log"
+	"os"
 )
 
 var (
 	from, to      string
 	limit, offset int64
+	resume        bool
 )
 
 func init() {
@@ -14,9 +17,20 @@ func init() {
 	flag.StringVar(&to, "to", "", "file to write to")
 	flag.Int64Var(&limit, "limit", 0, "limit of bytes to copy")
 	flag.Int64Var(&offset, "offset", 0, "offset in input file")
+	flag.BoolVar(&resume, "resume", false, "continue interrupted copying, verifying already copied data;\n"+
+		"data is written to <to>.part, which is kept on failure and renamed to <to> when done")
 }
 
 func main() {
 	flag.Parse()
-	// Place your code here
+
+	if from == "" || to == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	opts := Options{Offset: offset, Limit: limit, Resume: resume, Progress: os.Stderr}
+	if err := CopyFile(from, to, opts); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ширина прогресс-бара в символах.
+const progressWidth = 50
+
+// progressBar считает записанные байты и перерисовывает строку прогресса при изменении процента.
+type progressBar struct {
+	total   int64
+	written int64
+	percent int
+	out     io.Writer
+}
+
+// newProgressBar создаёт прогресс-бар, в котором уже записано written байт из total.
+func newProgressBar(total, written int64, out io.Writer) *progressBar {
+	bar := &progressBar{total: total, written: written, percent: -1, out: out}
+	bar.render()
+
+	return bar
+}
+
+func (bar *progressBar) Write(p []byte) (int, error) {
+	bar.written += int64(len(p))
+	bar.render()
+
+	return len(p), nil
+}
+
+// Finish завершает строку прогресса.
+func (bar *progressBar) Finish() {
+	fmt.Fprintln(bar.out)
+}
+
+func (bar *progressBar) render() {
+	percent := 100
+	if bar.total > 0 {
+		percent = int(bar.written * 100 / bar.total)
+	}
+
+	if percent == bar.percent {
+		return
+	}
+	bar.percent = percent
+
+	filled := percent * progressWidth / 100
+	fmt.Fprintf(bar.out, "\r[%s%s] %3d%%", strings.Repeat("=", filled), strings.Repeat(" ", progressWidth-filled), percent)
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProgressBar(t *testing.T) {
+	t.Run("renders percent changes", func(t *testing.T) {
+		var out bytes.Buffer
+		bar := newProgressBar(200, 0, &out)
+
+		_, _ = bar.Write(make([]byte, 100))
+		_, _ = bar.Write(make([]byte, 1))
+		_, _ = bar.Write(make([]byte, 99))
+		bar.Finish()
+
+		frames := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\r")[1:]
+		require.Equal(t, []string{
+			"[" + strings.Repeat(" ", progressWidth) + "]   0%",
+			"[" + strings.Repeat("=", progressWidth/2) + strings.Repeat(" ", progressWidth/2) + "]  50%",
+			"[" + strings.Repeat("=", progressWidth) + "] 100%",
+		}, frames)
+	})
+
+	t.Run("empty copy", func(t *testing.T) {
+		var out bytes.Buffer
+		newProgressBar(0, 0, &out).Finish()
+
+		require.Equal(t, "\r["+strings.Repeat("=", progressWidth)+"] 100%\n", out.String())
+	})
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hw07")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), bufferSize/4)
+	from := filepath.Join(dir, "input.bin")
+	require.NoError(t, ioutil.WriteFile(from, data, 0o600))
+
+	to := filepath.Join(dir, "out.bin")
+	part := to + partSuffix
+
+	// подготовить файлы перед копированием
+	prepare := func(t *testing.T, toData, partData []byte) {
+		t.Helper()
+
+		for path, content := range map[string][]byte{to: toData, part: partData} {
+			_ = os.Remove(path)
+			if content != nil {
+				require.NoError(t, ioutil.WriteFile(path, content, 0o600))
+			}
+		}
+	}
+
+	requireCopied := func(t *testing.T, expected []byte) {
+		t.Helper()
+
+		actual, err := ioutil.ReadFile(to)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+
+		_, err = os.Stat(part)
+		require.True(t, os.IsNotExist(err), "temporary file left")
+	}
+
+	t.Run("continue from part", func(t *testing.T) {
+		prepare(t, nil, data[:len(data)/2])
+
+		var progress bytes.Buffer
+		require.NoError(t, CopyFile(from, to, Options{Resume: true, Progress: &progress}))
+
+		requireCopied(t, data)
+		// прогресс начинается с уже скопированной половины
+		require.True(t, strings.HasPrefix(progress.String(), "\r["+strings.Repeat("=", progressWidth/2)+strings.Repeat(" ", progressWidth/2)+"]  50%"))
+	})
+
+	t.Run("corrupted part", func(t *testing.T) {
+		corrupted := append([]byte(nil), data[:100]...)
+		corrupted[50] = 'x'
+		prepare(t, nil, corrupted)
+
+		require.NoError(t, CopyFile(from, to, Options{Resume: true}))
+		requireCopied(t, data)
+	})
+
+	t.Run("part longer than source range", func(t *testing.T) {
+		prepare(t, nil, data)
+
+		require.NoError(t, CopyFile(from, to, Options{Offset: 10, Limit: 100, Resume: true}))
+		requireCopied(t, data[10:110])
+	})
+
+	t.Run("partial destination", func(t *testing.T) {
+		prepare(t, data[:1000], nil)
+
+		require.NoError(t, CopyFile(from, to, Options{Resume: true}))
+		requireCopied(t, data)
+	})
+
+	t.Run("complete destination", func(t *testing.T) {
+		prepare(t, data[16:], nil)
+
+		old := time.Now().Add(-time.Hour).Truncate(time.Second)
+		require.NoError(t, os.Chtimes(to, old, old))
+
+		require.NoError(t, CopyFile(from, to, Options{Offset: 16, Resume: true}))
+		requireCopied(t, data[16:])
+
+		// файл не переписывался
+		info, err := os.Stat(to)
+		require.NoError(t, err)
+		require.Equal(t, old, info.ModTime())
+	})
+
+	t.Run("failed copy", func(t *testing.T) {
+		// на месте копии каталог, писать в него нельзя
+		busy := filepath.Join(dir, "busy")
+		require.NoError(t, os.MkdirAll(filepath.Join(busy, "child"), 0o700))
+
+		for _, opts := range []Options{{}, {Resume: true}} {
+			require.Error(t, CopyFile(from, busy, opts))
+			_, err := os.Stat(busy + partSuffix)
+			require.True(t, os.IsNotExist(err))
+		}
+	})
+
+	t.Run("without resume", func(t *testing.T) {
+		// toPath перезаписывается напрямую, временный файл не используется
+		prepare(t, []byte("old content"), data[:100])
+
+		require.NoError(t, CopyFile(from, to, Options{Limit: 50}))
+
+		actual, err := ioutil.ReadFile(to)
+		require.NoError(t, err)
+		require.Equal(t, data[:50], actual)
+
+		actual, err = ioutil.ReadFile(part)
+		require.NoError(t, err)
+		require.Equal(t, data[:100], actual)
+	})
+
+	t.Run("symlink destination", func(t *testing.T) {
+		target := filepath.Join(dir, "target.bin")
+		link := filepath.Join(dir, "link.bin")
+		require.NoError(t, ioutil.WriteFile(target, []byte("old content"), 0o600))
+		require.NoError(t, os.Symlink(target, link))
+
+		for _, opts := range []Options{{Limit: 50}, {Limit: 100, Resume: true}} {
+			require.NoError(t, CopyFile(from, link, opts))
+
+			// ссылка остаётся ссылкой, данные пишутся в её цель
+			info, err := os.Lstat(link)
+			require.NoError(t, err)
+			require.NotZero(t, info.Mode()&os.ModeSymlink)
+
+			actual, err := ioutil.ReadFile(target)
+			require.NoError(t, err)
+			require.Equal(t, data[:opts.Limit], actual)
+
+			_, err = os.Lstat(link + partSuffix)
+			require.True(t, os.IsNotExist(err))
+		}
+	})
+
+	t.Run("file mode", func(t *testing.T) {
+		// права нового файла - 0666 с учётом umask, как у созданного здесь
+		probe := filepath.Join(dir, "probe")
+		probeFile, err := os.OpenFile(probe, os.O_WRONLY|os.O_CREATE, 0o666)
+		require.NoError(t, err)
+		require.NoError(t, probeFile.Close())
+		probeInfo, err := os.Stat(probe)
+		require.NoError(t, err)
+
+		requireMode := func(t *testing.T, expected os.FileMode) {
+			t.Helper()
+
+			info, err := os.Stat(to)
+			require.NoError(t, err)
+			require.Equal(t, expected, info.Mode().Perm())
+		}
+
+		for _, opts := range []Options{{}, {Resume: true}} {
+			prepare(t, nil, nil)
+			require.NoError(t, CopyFile(from, to, opts))
+			requireMode(t, probeInfo.Mode().Perm())
+
+			// существующий файл сохраняет свои права
+			prepare(t, data[:100], nil)
+			require.NoError(t, os.Chmod(to, 0o640))
+			require.NoError(t, CopyFile(from, to, opts))
+			requireCopied(t, data)
+			requireMode(t, 0o640)
+		}
+	})
+}